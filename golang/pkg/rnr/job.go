@@ -12,23 +12,28 @@ import (
 
 	"github.com/mplzik/rnr/golang/pkg/pb"
 	proto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 var (
 	ErrJobNotRunning     = errors.New("job is not running")
 	ErrJobAlreadyStarted = errors.New("job was already started")
+	ErrTaskNotFound      = errors.New("task not found")
 )
 
 type Job struct {
-	pbMutex  sync.Mutex
-	job      pb.Job
-	root     *Task
-	oldProto *pb.Task
-	err      error
-	done     chan struct{}
+	pbMutex   sync.Mutex
+	job       pb.Job
+	root      TaskInterface
+	pollMutex sync.Mutex // guards oldProto and serializes polls
+	oldProto  *pb.Task
+	runMutex  sync.Mutex // guards err and done
+	err       error
+	done      chan struct{}
+	pools     resourcePools
 }
 
-func NewJob(root *Task) *Job {
+func NewJob(root TaskInterface) *Job {
 	return &Job{
 		job: pb.Job{
 			Version: 1,
@@ -58,6 +63,11 @@ func (j *Job) Proto(updater func(*pb.Job)) *pb.Job {
 func taskDiff(path []string, old *pb.Task, new *pb.Task) []string {
 	var ret []string
 
+	if old == new {
+		// Unchanged subtrees are shared between task snapshots.
+		return nil
+	}
+
 	oldState := "(new)"
 	newState := "(deleted)"
 	oldMessage := ""
@@ -121,9 +131,23 @@ func taskDiff(path []string, old *pb.Task, new *pb.Task) []string {
 }
 
 func (j *Job) Poll(ctx context.Context) {
-	j.root.Poll(ctx)
+	j.pollMutex.Lock()
+	defer j.pollMutex.Unlock()
+
+	// Tasks recover from their own panics; this only guards against custom root task implementations.
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Recovered from panic while polling the job: %v", r)
+		}
+	}()
 
-	newProto := j.root.Proto(nil)
+	// Release the pool units of the tasks that have stopped running or have been removed before the schedulers try to
+	// acquire them.
+	j.pools.reconcile(j.root)
+	j.root.Poll(withPools(ctx, &j.pools))
+
+	// Snapshots share unchanged subtrees, which lets taskDiff skip them.
+	newProto := taskProto(j.root)
 	// Calculate diff and post state changes
 	diff := taskDiff([]string{newProto.GetName()}, j.oldProto, newProto)
 
@@ -141,47 +165,193 @@ func (j *Job) TaskRequest(r *pb.TaskRequest) error {
 		return fmt.Errorf("root task not configured")
 	}
 
-	for _, i := range r.Path {
-		task = task.GetChild(i)
+	if r.Pattern != "" {
+		return j.groupTaskRequest(r)
+	}
+
+	task = Find(task, r.Path)
+	if task == nil {
+		return fmt.Errorf("%w: %v", ErrTaskNotFound, r.Path)
+	}
+
+	return applyTaskRequest(task, r)
+}
+
+// ApprovalRequest records an operator's decision on the approval gate at the request's path.
+func (j *Job) ApprovalRequest(r *pb.ApprovalRequest) error {
+	task := j.Find(r.Path)
+	if task == nil {
+		return fmt.Errorf("%w: %v", ErrTaskNotFound, r.Path)
+	}
+
+	gate, ok := task.(Gate)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrNotAGate, r.Path)
+	}
+
+	return gate.Decide(r.Decision, r.Approver, r.Comment)
+}
+
+// WaitRequest skips or extends the wait of the wait task at the request's path.
+func (j *Job) WaitRequest(r *pb.WaitRequest) error {
+	task := j.Find(r.Path)
+	if task == nil {
+		return fmt.Errorf("%w: %v", ErrTaskNotFound, r.Path)
+	}
+
+	waiter, ok := task.(Waiter)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrNotAWaitTask, r.Path)
+	}
+
+	switch {
+	case r.Skip:
+		return waiter.SkipWait()
+	case r.Extend != nil:
+		return waiter.ExtendWait(r.Extend.AsDuration())
+	}
+
+	return fmt.Errorf("%w: neither skip nor extend is set", ErrInvalidWait)
+}
 
-		if task == nil {
-			return fmt.Errorf("task %v not found", r.Path)
+// PauseRequest pauses or resumes the task at the request's path along with its subtree; see Pause and Resume.
+func (j *Job) PauseRequest(r *pb.PauseRequest) error {
+	task := j.Find(r.Path)
+	if task == nil {
+		return fmt.Errorf("%w: %v", ErrTaskNotFound, r.Path)
+	}
+
+	if r.Resume {
+		return Resume(task)
+	}
+
+	return Pause(task, r.Suspend)
+}
+
+// PriorityRequest changes the priority of the task at the request's path, e.g. to start a pending task sooner.
+func (j *Job) PriorityRequest(r *pb.PriorityRequest) error {
+	task := j.Find(r.Path)
+	if task == nil {
+		return fmt.Errorf("%w: %v", ErrTaskNotFound, r.Path)
+	}
+
+	updateProto(task, pb.TransitionSource_SOURCE_REQUEST, func(p *pb.Task) *pb.Task {
+		p.Priority = r.Priority
+		return p
+	})
+
+	return nil
+}
+
+// groupTaskRequest applies the request to all the tasks matching its pattern.
+func (j *Job) groupTaskRequest(r *pb.TaskRequest) error {
+	matches, err := j.Query(r.Pattern)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, r.Pattern)
+	}
+
+	// Apply the request to as many tasks as possible and report the first error.
+	var firstErr error
+	failed := 0
+	for _, m := range matches {
+		if err := applyTaskRequest(m.Task, r); err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
 		}
 	}
 
-	if r.State != pb.TaskState_UNKNOWN {
-		task.SetState(r.State)
+	if firstErr != nil {
+		return fmt.Errorf("request failed for %d of %d tasks: %w", failed, len(matches), firstErr)
 	}
 
 	return nil
 }
 
+// applyTaskRequest applies the request to a single task.
+func applyTaskRequest(task TaskInterface, r *pb.TaskRequest) error {
+	if len(r.Params) == 0 {
+		if r.State == pb.TaskState_UNKNOWN {
+			return nil
+		}
+		return changeState(task, r.State, pb.TransitionSource_SOURCE_REQUEST, r.Force)
+	}
+
+	// Change the parameters and the state at once, so that neither is changed if the other is rejected.
+	var then func(*pb.Task) error
+	if r.State != pb.TaskState_UNKNOWN {
+		transitions := taskTransitions(task)
+		then = func(p *pb.Task) error {
+			return transition(p, transitions, r.State, r.Force)
+		}
+	}
+
+	return setParams(task, pb.TransitionSource_SOURCE_REQUEST, r.Params, then)
+}
+
+// Query returns the tasks matching `pattern` and all the predicates; see Query.
+func (j *Job) Query(pattern string, predicates ...TaskPredicate) ([]TaskPath, error) {
+	return Query(j.root, pattern, predicates...)
+}
+
+// Find returns the task at `path` relative to the root task, or nil if there's no such task.
+func (j *Job) Find(path []string) TaskInterface {
+	return Find(j.root, path)
+}
+
+// Output returns the output `key` of the task at `path` relative to the root task.
+func (j *Job) Output(path []string, key string) (*structpb.Value, error) {
+	task := j.Find(path)
+	if task == nil {
+		return nil, fmt.Errorf("%w: %v", ErrTaskNotFound, path)
+	}
+
+	return taskOutput(task, key)
+}
+
 // Err returns whatever error might have happened after Start.
-func (j *Job) Err() error { return j.err }
+func (j *Job) Err() error {
+	j.runMutex.Lock()
+	defer j.runMutex.Unlock()
+
+	return j.err
+}
 
 // Start is a shortcut for setting the root task to "running" state.
 func (j *Job) Start(ctx context.Context, pollInterval time.Duration) error {
+	j.runMutex.Lock()
+	defer j.runMutex.Unlock()
+
 	if j.done != nil {
 		return ErrJobAlreadyStarted
 	}
 
-	j.root.SetState(pb.TaskState_RUNNING)
+	setState(j.root, pb.TaskState_RUNNING, pb.TransitionSource_SOURCE_SCHEDULER)
 
-	j.done = make(chan struct{})
+	done := make(chan struct{})
+	j.done = done
 
 	go func() {
 		ticker := time.NewTicker(pollInterval)
 		defer ticker.Stop()
+		// Don't leave any background work behind once the job stops being polled.
+		defer j.root.Cancel()
 
 		for {
 			select {
-			case <-j.done:
+			case <-done:
 				// job was stopped by calling stopFn
 				return
 
 			case <-ctx.Done():
 				// job stopp due to context being done (e.g. cancelled or timed out)
+				j.runMutex.Lock()
 				j.err = ctx.Err()
+				j.runMutex.Unlock()
 				return
 
 			case <-ticker.C:
@@ -195,6 +365,9 @@ func (j *Job) Start(ctx context.Context, pollInterval time.Duration) error {
 
 // Stop stops the running job
 func (j *Job) Stop() error {
+	j.runMutex.Lock()
+	defer j.runMutex.Unlock()
+
 	if j.done == nil {
 		return ErrJobNotRunning
 	}
@@ -204,4 +377,9 @@ func (j *Job) Stop() error {
 }
 
 // Wait waits for the Job to finish.
-func (j *Job) Wait() <-chan struct{} { return j.done }
+func (j *Job) Wait() <-chan struct{} {
+	j.runMutex.Lock()
+	defer j.runMutex.Unlock()
+
+	return j.done
+}
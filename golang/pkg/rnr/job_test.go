@@ -3,6 +3,15 @@ package rnr
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -10,7 +19,7 @@ import (
 )
 
 func TestJob(t *testing.T) {
-	var pollCount int
+	var pollCount int32
 
 	mt := newMockTask("root", pb.TaskState_RUNNING, &pollCount)
 
@@ -28,24 +37,106 @@ func TestJob(t *testing.T) {
 		t.Fatalf("expecting ErrJobAlreadyStarted, got %v", err)
 	}
 
-	var stopErr error
+	stopErr := make(chan error, 1)
 
 	go func() {
 		for {
-			if pollCount == 5 {
-				stopErr = j.Stop()
+			if atomic.LoadInt32(&pollCount) >= 5 {
+				stopErr <- j.Stop()
 				break
 			}
+			time.Sleep(time.Millisecond)
 		}
 	}()
 
 	<-j.Wait()
 
-	if stopErr != nil {
-		t.Fatalf("unexpected error: %v", stopErr)
+	if err := <-stopErr; err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
 	if err := j.Stop(); !errors.Is(err, ErrJobNotRunning) {
 		t.Fatalf("expecting rnr.ErrJobNotRunning, got %v", err)
 	}
 }
+
+func TestJob_Concurrency(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nt := NewNestedTask("root", NestedTaskOptions{Parallelism: 5, CompleteAll: true})
+	j := NewJob(nt)
+	ws := NewRnrWebserver(j)
+
+	if err := j.Start(ctx, time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer j.Stop()
+
+	const n = 100
+	var wg sync.WaitGroup
+
+	// Add children while the job is being polled
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			if err := nt.Add(newMockTask(fmt.Sprintf("child %d", i), pb.TaskState_SUCCESS, nil)); err != nil {
+				t.Errorf("unexpected error when adding child %d: %v", i, err)
+			}
+		}
+	}()
+
+	// Mutate the children over HTTP
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			body := fmt.Sprintf(`{"path": ["child %d"], "state": "RUNNING"}`, i)
+			ws.tasksHandler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body)))
+		}
+	}()
+
+	// Read the whole tree over HTTP
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			rec := httptest.NewRecorder()
+			ws.tasksHandler(rec, httptest.NewRequest(http.MethodGet, "/tasks", nil))
+			if rec.Code != http.StatusOK {
+				t.Errorf("unexpected status code %d", rec.Code)
+			}
+		}
+	}()
+
+	wg.Wait()
+}
+
+func BenchmarkJob_Poll(b *testing.B) {
+	ctx := context.Background()
+
+	for _, n := range []int{100, 1000, 10000} {
+		// A two-level tree of `n` leaves, most of them done
+		root := NewNestedTask("root", NestedTaskOptions{Parallelism: 10, CompleteAll: true})
+		groups := n / 100
+		for g := 0; g < groups; g++ {
+			group := NewNestedTask(fmt.Sprintf("group %d", g), NestedTaskOptions{Parallelism: 10, CompleteAll: true})
+			root.Add(group)
+			for i := 0; i < 100; i++ {
+				group.Add(newMockTask(fmt.Sprintf("task %d", i), pb.TaskState_RUNNING, nil))
+			}
+		}
+		j := NewJob(root)
+		root.SetState(pb.TaskState_RUNNING)
+		log.SetOutput(ioutil.Discard)
+		j.Poll(ctx)
+		log.SetOutput(os.Stderr)
+
+		b.Run(fmt.Sprintf("Job.Poll - %6d tasks", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				j.Poll(ctx)
+			}
+		})
+	}
+}
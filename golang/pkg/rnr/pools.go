@@ -0,0 +1,231 @@
+package rnr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	ErrPoolNotFound = errors.New("resource pool not found")
+	ErrPoolExists   = errors.New("resource pool already exists")
+)
+
+// resourcePools are the named semaphores of a job, limiting the number of tasks using a scarce resource (e.g. database
+// migration slots or a rate-limited API) at once across the whole job. Tasks declare the units they need using
+// UsePool; the schedulers (NestedTask, DAGTask) only start a task once it has acquired all of them, and the units are
+// released once the task stops running or is removed from the job. Paused tasks keep their units.
+//
+// The pools are passed down to the schedulers in the context of Job.Poll, so they're only enforced for tasks polled
+// as part of a job.
+type resourcePools struct {
+	mu    sync.Mutex
+	pools map[string]*resourcePool
+}
+
+type resourcePool struct {
+	name     string
+	capacity int
+	used     int
+	holders  map[TaskInterface]*poolUser
+	waiters  map[TaskInterface]*poolUser
+}
+
+type poolUser struct {
+	units int
+	since time.Time
+}
+
+type poolsKey struct{}
+
+// withPools returns a context passing the pools down to the schedulers.
+func withPools(ctx context.Context, pools *resourcePools) context.Context {
+	return context.WithValue(ctx, poolsKey{}, pools)
+}
+
+// poolsFrom returns the pools passed in the context, or nil if there are none.
+func poolsFrom(ctx context.Context) *resourcePools {
+	pools, _ := ctx.Value(poolsKey{}).(*resourcePools)
+	return pools
+}
+
+// UsePool declares that the task needs `units` units of the job's resource pool `pool` to run; 0 units remove the
+// claim. It should be called while the task isn't running.
+func (task *Task) UsePool(pool string, units int) {
+	task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+		var claims []*pb.ResourceClaim
+		for _, c := range p.Resources {
+			if c.Pool != pool {
+				claims = append(claims, c)
+			}
+		}
+		if units > 0 {
+			claims = append(claims, &pb.ResourceClaim{Pool: pool, Units: int32(units)})
+		}
+		p.Resources = claims
+		return p
+	})
+}
+
+// AddPool registers a resource pool with `capacity` units.
+func (j *Job) AddPool(name string, capacity int) error {
+	j.pools.mu.Lock()
+	defer j.pools.mu.Unlock()
+
+	if _, ok := j.pools.pools[name]; ok {
+		return fmt.Errorf("%w: %s", ErrPoolExists, name)
+	}
+	if j.pools.pools == nil {
+		j.pools.pools = map[string]*resourcePool{}
+	}
+	j.pools.pools[name] = &resourcePool{
+		name:     name,
+		capacity: capacity,
+		holders:  map[TaskInterface]*poolUser{},
+		waiters:  map[TaskInterface]*poolUser{},
+	}
+
+	return nil
+}
+
+// Pools returns the state of the job's resource pools, including the paths of the tasks holding and waiting for them.
+func (j *Job) Pools() *pb.Pools {
+	paths := map[TaskInterface][]string{}
+	Walk(j.root, func(path []string, task TaskInterface) error {
+		paths[task] = path
+		return nil
+	})
+
+	j.pools.mu.Lock()
+	defer j.pools.mu.Unlock()
+
+	users := func(m map[TaskInterface]*poolUser) []*pb.PoolUser {
+		var ret []*pb.PoolUser
+		for task, u := range m {
+			path, ok := paths[task]
+			if !ok {
+				// Removed from the job.
+				path = []string{task.Name()}
+			}
+			ret = append(ret, &pb.PoolUser{Path: path, Units: int32(u.units), Since: timestamppb.New(u.since)})
+		}
+		sort.SliceStable(ret, func(i, k int) bool { return ret[i].Since.AsTime().Before(ret[k].Since.AsTime()) })
+		return ret
+	}
+
+	ret := &pb.Pools{}
+	for _, p := range j.pools.pools {
+		ret.Pools = append(ret.Pools, &pb.Pool{
+			Name:     p.name,
+			Capacity: int32(p.capacity),
+			Used:     int32(p.used),
+			Holders:  users(p.holders),
+			Waiters:  users(p.waiters),
+		})
+	}
+	sort.Slice(ret.Pools, func(i, k int) bool { return ret.Pools[i].Name < ret.Pools[k].Name })
+
+	return ret
+}
+
+// acquire acquires the units claimed by `task`, either all of them or none. If they're not available, the task is
+// recorded as waiting for them. Claims which can never be satisfied are reported as errors.
+func (rp *resourcePools) acquire(task TaskInterface, claims []*pb.ResourceClaim) (bool, error) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	available := true
+	for _, c := range claims {
+		p, ok := rp.pools[c.Pool]
+		if !ok {
+			return false, fmt.Errorf("%w: %s", ErrPoolNotFound, c.Pool)
+		}
+		if int(c.Units) > p.capacity {
+			return false, fmt.Errorf("%d units of resource pool '%s' needed, but it only has %d", c.Units, c.Pool, p.capacity)
+		}
+		if _, held := p.holders[task]; !held && p.used+int(c.Units) > p.capacity {
+			available = false
+		}
+	}
+
+	now := timeNow()
+	for _, c := range claims {
+		p := rp.pools[c.Pool]
+		if _, held := p.holders[task]; held {
+			continue
+		}
+		if !available {
+			if _, ok := p.waiters[task]; !ok {
+				p.waiters[task] = &poolUser{units: int(c.Units), since: now}
+			}
+			continue
+		}
+		delete(p.waiters, task)
+		p.holders[task] = &poolUser{units: int(c.Units), since: now}
+		p.used += int(c.Units)
+	}
+
+	return available, nil
+}
+
+// reconcile releases the units held by the tasks which are neither running nor paused, or which are no longer in the
+// job's tree under `root`, and forgets the waiting tasks which are no longer pending or in the tree.
+func (rp *resourcePools) reconcile(root TaskInterface) {
+	rp.mu.Lock()
+	empty := len(rp.pools) == 0
+	rp.mu.Unlock()
+	if empty {
+		return
+	}
+
+	// Removed tasks keep their last state, so they have to be looked for in the tree.
+	inTree := map[TaskInterface]bool{}
+	Walk(root, func(_ []string, task TaskInterface) error {
+		inTree[task] = true
+		return nil
+	})
+
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	for _, p := range rp.pools {
+		for task, u := range p.holders {
+			if sched := taskSchedState(taskProto(task)); !inTree[task] || (sched != RUNNING && sched != PAUSED) {
+				delete(p.holders, task)
+				p.used -= u.units
+			}
+		}
+		for task := range p.waiters {
+			if !inTree[task] || taskSchedState(taskProto(task)) != PENDING {
+				delete(p.waiters, task)
+			}
+		}
+	}
+}
+
+// acquirePools acquires the units of the resource pools a scheduler's child needs to start. It returns false if the
+// child has to wait for them; a child whose claims can never be satisfied fails.
+func acquirePools(ctx context.Context, child TaskInterface, cpb *pb.Task) bool {
+	pools := poolsFrom(ctx)
+	if pools == nil || len(cpb.Resources) == 0 {
+		return true
+	}
+
+	ok, err := pools.acquire(child, cpb.Resources)
+	if err != nil {
+		updateProto(child, pb.TransitionSource_SOURCE_SCHEDULER, func(p *pb.Task) *pb.Task {
+			p.State = pb.TaskState_FAILED
+			p.Message = err.Error()
+			return p
+		})
+		return false
+	}
+
+	return ok
+}
@@ -0,0 +1,119 @@
+package rnr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+// newPoolJob returns a job with two branches, each with a child needing a unit of the `db` pool.
+func newPoolJob(t *testing.T) (*Job, *Task, *Task) {
+	root := NewNestedTask("root", NestedTaskOptions{Parallelism: 2})
+	var children []*Task
+	for _, name := range []string{"a", "b"} {
+		branch := NewNestedTask(name, NestedTaskOptions{})
+		child := newMockTask("migrate", pb.TaskState_RUNNING, nil)
+		child.UsePool("db", 1)
+		branch.Add(child)
+		root.Add(branch)
+		children = append(children, child)
+	}
+
+	job := NewJob(root)
+	if err := job.AddPool("db", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := job.AddPool("db", 2); !errors.Is(err, ErrPoolExists) {
+		t.Errorf("expecting ErrPoolExists, got %v", err)
+	}
+	root.SetState(pb.TaskState_RUNNING)
+
+	return job, children[0], children[1]
+}
+
+func TestJob_Pools(t *testing.T) {
+	ctx := context.Background()
+	job, a, b := newPoolJob(t)
+
+	job.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{a, b}, []pb.TaskState{pb.TaskState_RUNNING, pb.TaskState_PENDING})
+
+	pools := job.Pools().Pools
+	if len(pools) != 1 || pools[0].Used != 1 || len(pools[0].Holders) != 1 || len(pools[0].Waiters) != 1 {
+		t.Fatalf("unexpected pools %v", pools)
+	}
+	if got := pools[0].Holders[0].Path; !reflect.DeepEqual(got, []string{"a", "migrate"}) {
+		t.Errorf("unexpected holder %v", got)
+	}
+	if got := pools[0].Waiters[0].Path; !reflect.DeepEqual(got, []string{"b", "migrate"}) {
+		t.Errorf("unexpected waiter %v", got)
+	}
+
+	// The units are handed over once the holder finishes.
+	a.SetState(pb.TaskState_SUCCESS)
+	job.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{a, b}, []pb.TaskState{pb.TaskState_SUCCESS, pb.TaskState_RUNNING})
+
+	pools = job.Pools().Pools
+	if len(pools[0].Holders) != 1 || len(pools[0].Waiters) != 0 {
+		t.Errorf("unexpected pools %v", pools)
+	}
+}
+
+func TestJob_PoolsRemovedHolder(t *testing.T) {
+	ctx := context.Background()
+	job, a, b := newPoolJob(t)
+
+	job.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{a, b}, []pb.TaskState{pb.TaskState_RUNNING, pb.TaskState_PENDING})
+
+	// The removed holder stays RUNNING, but its units are released anyway.
+	if err := job.root.(*NestedTask).Remove("a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	job.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{a, b}, []pb.TaskState{pb.TaskState_RUNNING, pb.TaskState_RUNNING})
+
+	pools := job.Pools().Pools
+	if pools[0].Used != 1 || len(pools[0].Holders) != 1 || len(pools[0].Waiters) != 0 {
+		t.Fatalf("unexpected pools %v", pools)
+	}
+	if got := pools[0].Holders[0].Path; !reflect.DeepEqual(got, []string{"b", "migrate"}) {
+		t.Errorf("unexpected holder %v", got)
+	}
+}
+
+func TestJob_PoolsUnsatisfiable(t *testing.T) {
+	ctx := context.Background()
+	job, a, b := newPoolJob(t)
+	a.UsePool("db", 2)
+	b.UsePool("db", 0)
+	b.UsePool("nope", 1)
+
+	job.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{a, b}, []pb.TaskState{pb.TaskState_FAILED, pb.TaskState_FAILED})
+	if got := b.Proto(nil).Message; !strings.Contains(got, ErrPoolNotFound.Error()) {
+		t.Errorf("unexpected message %q", got)
+	}
+}
+
+func TestRnrWebServer_Pools(t *testing.T) {
+	job, _, _ := newPoolJob(t)
+	job.Poll(context.Background())
+	ws := NewRnrWebserver(job)
+
+	rec := httptest.NewRecorder()
+	ws.poolsHandler(rec, httptest.NewRequest(http.MethodGet, "/pools", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", rec.Code)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `"name":"db"`) || !strings.Contains(body, `"migrate"`) {
+		t.Errorf("unexpected body %s", body)
+	}
+}
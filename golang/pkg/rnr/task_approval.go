@@ -0,0 +1,114 @@
+package rnr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	ErrNotAGate            = errors.New("task is not an approval gate")
+	ErrNotAwaitingApproval = errors.New("task is not waiting for approval")
+	ErrNoDecision          = errors.New("no decision made")
+)
+
+// Gate is implemented by tasks waiting for an operator's decision, such as ApprovalTask.
+type Gate interface {
+	// Decide records the operator's decision and finishes the task accordingly.
+	Decide(decision pb.ApprovalDecision, approver, comment string) error
+}
+
+// ApprovalTask blocks its parent until an operator approves or rejects it. Once started, it waits in the
+// ACTION_NEEDED state; approving it makes it succeed and rejecting it makes it fail. The decision, along with
+// the approver, is recorded in the task's protobuf.
+//
+// The decision can only be made using Decide (or the approval HTTP API), not by changing the task's state directly.
+type ApprovalTask struct {
+	*Task
+}
+
+func NewApprovalTask(name string) *ApprovalTask {
+	ret := &ApprovalTask{}
+	ret.Task = NewTask(name, false, ret.poll)
+	ret.SetTransitions(approvalTaskTransitions)
+	ret.Proto(func(p *pb.Task) *pb.Task {
+		p.Approval = &pb.Approval{}
+		return p
+	})
+
+	return ret
+}
+
+// approvalTaskTransitions don't allow finishing a gate by changing its state; the operator can only reset or skip it.
+var approvalTaskTransitions = Transitions{
+	pb.TaskState_UNKNOWN:       allStates,
+	pb.TaskState_PENDING:       {pb.TaskState_RUNNING, pb.TaskState_SKIPPED},
+	pb.TaskState_RUNNING:       {pb.TaskState_PENDING, pb.TaskState_SKIPPED},
+	pb.TaskState_ACTION_NEEDED: {pb.TaskState_PENDING, pb.TaskState_SKIPPED},
+	pb.TaskState_SUCCESS:       {pb.TaskState_PENDING},
+	pb.TaskState_FAILED:        {pb.TaskState_PENDING},
+	pb.TaskState_SKIPPED:       {pb.TaskState_PENDING},
+}
+
+func (at *ApprovalTask) poll(ctx context.Context, task *Task) {
+	task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+		if p.State != pb.TaskState_RUNNING {
+			return p
+		}
+
+		// A new run needs a new decision.
+		p.State = pb.TaskState_ACTION_NEEDED
+		p.Message = "waiting for approval"
+		p.Approval = &pb.Approval{}
+		return p
+	})
+}
+
+// Decide records the operator's decision; it's only allowed while the task is waiting for it.
+func (at *ApprovalTask) Decide(decision pb.ApprovalDecision, approver, comment string) error {
+	if decision == pb.ApprovalDecision_DECISION_PENDING {
+		return ErrNoDecision
+	}
+
+	var err error
+	at.updateFrom(pb.TransitionSource_SOURCE_REQUEST, func(p *pb.Task) *pb.Task {
+		err = nil
+		if p.State != pb.TaskState_ACTION_NEEDED {
+			err = fmt.Errorf("%w: task '%s' is %s", ErrNotAwaitingApproval, p.Name, p.State)
+			return p
+		}
+
+		p.Approval = &pb.Approval{
+			Decision:  decision,
+			Approver:  approver,
+			Timestamp: timestamppb.New(timeNow()),
+			Comment:   comment,
+		}
+		if decision == pb.ApprovalDecision_DECISION_APPROVED {
+			p.State = pb.TaskState_SUCCESS
+			p.Message = fmt.Sprintf("approved by %s", approver)
+		} else {
+			p.State = pb.TaskState_FAILED
+			p.Message = fmt.Sprintf("rejected by %s", approver)
+		}
+		if comment != "" {
+			p.Message += ": " + comment
+		}
+		return p
+	})
+
+	return err
+}
+
+// Approve is a shortcut for approving the task.
+func (at *ApprovalTask) Approve(approver, comment string) error {
+	return at.Decide(pb.ApprovalDecision_DECISION_APPROVED, approver, comment)
+}
+
+// Reject is a shortcut for rejecting the task.
+func (at *ApprovalTask) Reject(approver, comment string) error {
+	return at.Decide(pb.ApprovalDecision_DECISION_REJECTED, approver, comment)
+}
@@ -0,0 +1,131 @@
+package rnr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+func TestApprovalTask(t *testing.T) {
+	ctx := context.Background()
+	nt := NewNestedTask("root", NestedTaskOptions{})
+	gate := NewApprovalTask("gate")
+	after := newMockTask("after", pb.TaskState_SUCCESS, nil)
+	nt.Add(gate)
+	nt.Add(after)
+	tasks := []TaskInterface{gate, after, nt}
+
+	if err := gate.Approve("alice", ""); !errors.Is(err, ErrNotAwaitingApproval) {
+		t.Errorf("expecting ErrNotAwaitingApproval before the gate is started, got %v", err)
+	}
+
+	nt.SetState(pb.TaskState_RUNNING)
+	for i := 0; i < 3; i++ {
+		nt.Poll(ctx)
+	}
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_ACTION_NEEDED, pb.TaskState_PENDING, pb.TaskState_RUNNING})
+
+	if err := gate.Decide(pb.ApprovalDecision_DECISION_PENDING, "alice", ""); !errors.Is(err, ErrNoDecision) {
+		t.Errorf("expecting ErrNoDecision, got %v", err)
+	}
+	if err := gate.Approve("alice", "looks good"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := gate.Reject("bob", ""); !errors.Is(err, ErrNotAwaitingApproval) {
+		t.Errorf("expecting ErrNotAwaitingApproval after a decision, got %v", err)
+	}
+
+	a := gate.Proto(nil).Approval
+	if a.Decision != pb.ApprovalDecision_DECISION_APPROVED || a.Approver != "alice" || a.Comment != "looks good" || a.Timestamp == nil {
+		t.Errorf("unexpected approval record: %v", a)
+	}
+
+	for i := 0; i < 2; i++ {
+		nt.Poll(ctx)
+	}
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_SUCCESS, pb.TaskState_SUCCESS, pb.TaskState_SUCCESS})
+
+	// A rerun requires a new decision.
+	gate.SetState(pb.TaskState_PENDING)
+	gate.SetState(pb.TaskState_RUNNING)
+	gate.Poll(ctx)
+	if a := gate.Proto(nil).Approval; a.Decision != pb.ApprovalDecision_DECISION_PENDING || a.Approver != "" {
+		t.Errorf("expecting the approval to be reset, got %v", a)
+	}
+	if err := gate.Reject("bob", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	compareTaskStates(t, []TaskInterface{gate}, []pb.TaskState{pb.TaskState_FAILED})
+}
+
+func TestRnrWebServer_Approval(t *testing.T) {
+	nt := NewNestedTask("root", NestedTaskOptions{})
+	gate := NewApprovalTask("gate")
+	nt.Add(gate)
+	nt.Add(newMockTask("other", pb.TaskState_SUCCESS, nil))
+	ws := NewRnrWebserver(NewJob(nt))
+
+	gate.SetState(pb.TaskState_RUNNING)
+	gate.Poll(context.Background())
+
+	post := func(handler http.HandlerFunc, body string, user string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		if user != "" {
+			req.SetBasicAuth(user, "secret")
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+
+	tests := []struct {
+		handler http.HandlerFunc
+		body    string
+		code    int
+	}{
+		// The state can't be changed to SUCCESS directly.
+		{ws.tasksHandler, `{"path": ["gate"], "state": "SUCCESS"}`, http.StatusConflict},
+		{ws.approvalHandler, `{"path": ["other"], "decision": "DECISION_APPROVED"}`, http.StatusBadRequest},
+		{ws.approvalHandler, `{"path": ["nope"], "decision": "DECISION_APPROVED"}`, http.StatusNotFound},
+		{ws.approvalHandler, `{"path": ["gate"]}`, http.StatusBadRequest},
+		{ws.approvalHandler, `{"path": ["gate"], "decision": "DECISION_APPROVED", "comment": "ok"}`, http.StatusOK},
+		{ws.approvalHandler, `{"path": ["gate"], "decision": "DECISION_REJECTED"}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		if code := post(tt.handler, tt.body, "carol"); code != tt.code {
+			t.Errorf("expecting status %d for %s, got %d", tt.code, tt.body, code)
+		}
+	}
+
+	if a := gate.Proto(nil).Approval; a.Approver != "carol" || a.Comment != "ok" {
+		t.Errorf("expecting approval by carol, got %v", a)
+	}
+
+	// The authenticated user takes precedence over the one claimed in the request.
+	gate.SetState(pb.TaskState_PENDING)
+	gate.SetState(pb.TaskState_RUNNING)
+	gate.Poll(context.Background())
+	if code := post(ws.approvalHandler, `{"path": ["gate"], "decision": "DECISION_APPROVED", "approver": "eve"}`, "carol"); code != http.StatusOK {
+		t.Fatalf("unexpected status %d", code)
+	}
+	if a := gate.Proto(nil).Approval; a.Approver != "carol" {
+		t.Errorf("expecting approval by carol, got %v", a)
+	}
+
+	// The identity provided by the web server takes precedence over the one claimed in the request.
+	ws.Identify = func(r *http.Request) string { return "dave" }
+	gate.SetState(pb.TaskState_PENDING)
+	gate.SetState(pb.TaskState_RUNNING)
+	gate.Poll(context.Background())
+	if code := post(ws.approvalHandler, `{"path": ["gate"], "decision": "DECISION_REJECTED", "approver": "eve"}`, ""); code != http.StatusOK {
+		t.Fatalf("unexpected status %d", code)
+	}
+	if a := gate.Proto(nil).Approval; a.Approver != "dave" || a.Decision != pb.ApprovalDecision_DECISION_REJECTED {
+		t.Errorf("expecting rejection by dave, got %v", a)
+	}
+}
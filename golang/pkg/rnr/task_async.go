@@ -8,30 +8,53 @@ import (
 
 type AsyncFunc func(context.Context, func(StateUpdateCallback) *pb.Task)
 
-func NewAsyncTask(name string, ctx context.Context, runsInSuccess bool, bgTask AsyncFunc) *Task {
-	parentCtx := ctx
-	var currentCtx context.Context
-	var cancel context.CancelFunc
-
-	ret := NewTask(name, false, func(ctx context.Context, task *Task) {
-		state := task.Proto(nil)
-
-		if state.State == pb.TaskState_RUNNING || (runsInSuccess && state.State == pb.TaskState_SUCCESS) {
-			if currentCtx == nil {
-				currentCtx, cancel = context.WithCancel(parentCtx)
-				go bgTask(currentCtx, func(cb StateUpdateCallback) *pb.Task {
-					return task.Proto(cb)
-				})
-			}
-		} else {
-			if currentCtx != nil {
-				cancel()
-				currentCtx = nil
-				cancel = nil
-			}
-		}
+// AsyncTask runs a function in a background goroutine while the task is running.
+//
+// The goroutine's context is derived from the context passed to NewAsyncTask and is cancelled together with the task's
+// own context, i.e. once the task stops running.
+type AsyncTask struct {
+	*Task
+	parentCtx context.Context
+	bgTask    AsyncFunc
+	taskCtx   context.Context // the task's context the background goroutine was started for
+}
 
-	})
+func NewAsyncTask(name string, ctx context.Context, runsInSuccess bool, bgTask AsyncFunc) *AsyncTask {
+	ret := &AsyncTask{
+		parentCtx: ctx,
+		bgTask:    bgTask,
+	}
+	ret.Task = NewTask(name, false, ret.poll)
+	if runsInSuccess {
+		ret.activeIn = func(state pb.TaskState) bool {
+			return state == pb.TaskState_SUCCESS || taskSchedState(&pb.Task{State: state}) == RUNNING
+		}
+	}
 
 	return ret
 }
+
+func (at *AsyncTask) poll(ctx context.Context, task *Task) {
+	// Inactive tasks get a cancelled context; a new context means the task was (re)started.
+	if ctx.Err() != nil || ctx == at.taskCtx {
+		return
+	}
+	at.taskCtx = ctx
+
+	bgCtx, cancel := context.WithCancel(at.parentCtx)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-bgCtx.Done():
+		}
+		cancel()
+	}()
+
+	go func() {
+		defer task.recoverPanic()
+
+		at.bgTask(bgCtx, func(cb StateUpdateCallback) *pb.Task {
+			return task.Proto(cb)
+		})
+	}()
+}
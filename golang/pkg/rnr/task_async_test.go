@@ -2,6 +2,7 @@ package rnr
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -12,12 +13,12 @@ const tick = 10 * time.Millisecond
 
 func TestAsyncTask_Lifecycle(t *testing.T) {
 	ctx := context.TODO()
-	running := false
+	var running int32
 	at := NewAsyncTask("Test async task", context.Background(), false, func(ctx context.Context, update func(StateUpdateCallback) *pb.Task) {
-		running = true
+		atomic.StoreInt32(&running, 1)
 		select {
 		case <-ctx.Done():
-			running = false
+			atomic.StoreInt32(&running, 0)
 		}
 		update(func(t *pb.Task) *pb.Task {
 			t.State = pb.TaskState_SUCCESS
@@ -27,7 +28,7 @@ func TestAsyncTask_Lifecycle(t *testing.T) {
 
 	at.Poll(ctx)
 	time.Sleep(tick)
-	if running != false {
+	if atomic.LoadInt32(&running) != 0 {
 		t.Errorf("async task expected not running was found running")
 	}
 
@@ -37,7 +38,7 @@ func TestAsyncTask_Lifecycle(t *testing.T) {
 	})
 	at.Poll(ctx)
 	time.Sleep(tick)
-	if running != true {
+	if atomic.LoadInt32(&running) != 1 {
 		t.Errorf("async task expected running was found not running")
 	}
 
@@ -47,19 +48,19 @@ func TestAsyncTask_Lifecycle(t *testing.T) {
 	})
 	at.Poll(ctx)
 	time.Sleep(tick)
-	if running != false {
+	if atomic.LoadInt32(&running) != 0 {
 		t.Errorf("async task expected not running anymore was found running")
 	}
 }
 
 func TestAsyncTask_BackgroundLifecycle(t *testing.T) {
 	ctx := context.TODO()
-	running := false
+	var running int32
 	at := NewAsyncTask("Test async task", context.Background(), true, func(ctx context.Context, update func(StateUpdateCallback) *pb.Task) {
-		running = true
+		atomic.StoreInt32(&running, 1)
 		select {
 		case <-ctx.Done():
-			running = false
+			atomic.StoreInt32(&running, 0)
 		}
 		update(func(t *pb.Task) *pb.Task {
 			t.State = pb.TaskState_SUCCESS
@@ -69,7 +70,7 @@ func TestAsyncTask_BackgroundLifecycle(t *testing.T) {
 
 	at.Poll(ctx)
 	time.Sleep(tick)
-	if running != false {
+	if atomic.LoadInt32(&running) != 0 {
 		t.Errorf("async task expected not running was found running")
 	}
 
@@ -79,7 +80,7 @@ func TestAsyncTask_BackgroundLifecycle(t *testing.T) {
 	})
 	at.Poll(ctx)
 	time.Sleep(tick)
-	if running != true {
+	if atomic.LoadInt32(&running) != 1 {
 		t.Errorf("async task expected running was found not running")
 	}
 
@@ -89,7 +90,7 @@ func TestAsyncTask_BackgroundLifecycle(t *testing.T) {
 	})
 	at.Poll(ctx)
 	time.Sleep(tick)
-	if running != true {
+	if atomic.LoadInt32(&running) != 1 {
 		t.Errorf("async task expected to be running in SUCCESS was found not running")
 	}
 
@@ -99,7 +100,7 @@ func TestAsyncTask_BackgroundLifecycle(t *testing.T) {
 	})
 	at.Poll(ctx)
 	time.Sleep(tick)
-	if running != false {
+	if atomic.LoadInt32(&running) != 0 {
 		t.Errorf("async task expected not running anymore was found running")
 	}
 }
@@ -0,0 +1,117 @@
+package rnr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// BranchOutput is the output of a BranchTask holding the name of the chosen branch.
+const BranchOutput = "branch"
+
+// BranchFunc chooses the branch to run by returning its name; an empty name means that no branch should run.
+// It can inspect anything, e.g. outputs of the earlier tasks using task.OutputOf.
+type BranchFunc func(ctx context.Context, task *Task) (string, error)
+
+// BranchTask runs one of its children (branches), chosen by a BranchFunc once the task starts; the other branches are
+// skipped. The BranchTask finishes in the state of the chosen branch, or succeeds right away if no branch was chosen.
+// An error returned by the BranchFunc fails the task.
+//
+// The chosen branch is published as the BranchOutput output of the task.
+type BranchTask struct {
+	*Task
+	choose  BranchFunc
+	chosen  TaskInterface
+	started time.Time // the start of the run the branch was chosen for
+}
+
+func NewBranchTask(name string, choose BranchFunc) *BranchTask {
+	ret := &BranchTask{choose: choose}
+	ret.Task = NewTask(name, true, ret.poll)
+
+	return ret
+}
+
+func (bt *BranchTask) poll(ctx context.Context, task *Task) {
+	tpb := task.snapshot()
+	if taskSchedState(tpb) != RUNNING {
+		return
+	}
+
+	// Choose the branch again if the BranchTask itself was rerun.
+	if started := tpb.Started.AsTime(); !started.Equal(bt.started) {
+		bt.started = started
+		if !bt.chooseBranch(ctx, task) {
+			return
+		}
+	}
+
+	if bt.chosen == nil {
+		task.SetState(pb.TaskState_SUCCESS)
+		return
+	}
+
+	bt.chosen.Poll(ctx)
+
+	if cpb := taskProto(bt.chosen); taskSchedState(cpb) == DONE {
+		task.SetState(cpb.State)
+	}
+}
+
+// chooseBranch calls the BranchFunc, skips the branches that weren't chosen and starts the chosen one.
+// It returns false if the choice has failed.
+func (bt *BranchTask) chooseBranch(ctx context.Context, task *Task) bool {
+	bt.chosen = nil
+
+	name, err := bt.choose(ctx, task)
+	if err == nil && name != "" {
+		if bt.chosen = task.GetChild(name); bt.chosen == nil {
+			err = fmt.Errorf("%w: branch '%s'", ErrTaskNotFound, name)
+		}
+	}
+	if err != nil {
+		task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+			p.State = pb.TaskState_FAILED
+			p.Message = fmt.Sprintf("choosing a branch failed: %s", err.Error())
+			return p
+		})
+		return false
+	}
+
+	for _, child := range task.Children() {
+		if child == bt.chosen {
+			// The branch might have run before, if the BranchTask is being rerun.
+			if taskSchedState(taskProto(child)) != PENDING {
+				if r, ok := child.(Resetter); ok {
+					r.Reset()
+				}
+				setState(child, pb.TaskState_PENDING, pb.TransitionSource_SOURCE_SCHEDULER)
+			}
+			setState(child, pb.TaskState_RUNNING, pb.TransitionSource_SOURCE_SCHEDULER)
+			continue
+		}
+		updateProto(child, pb.TransitionSource_SOURCE_SCHEDULER, func(p *pb.Task) *pb.Task {
+			p.State = pb.TaskState_SKIPPED
+			p.Message = "branch not taken"
+			return p
+		})
+	}
+
+	task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+		if name == "" {
+			p.Message = "no branch taken"
+		} else {
+			p.Message = fmt.Sprintf("branch '%s' taken", name)
+		}
+		if p.Outputs == nil {
+			p.Outputs = map[string]*structpb.Value{}
+		}
+		p.Outputs[BranchOutput] = structpb.NewStringValue(name)
+		return p
+	})
+
+	return true
+}
@@ -0,0 +1,80 @@
+package rnr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+func newBranchTestTask(choose BranchFunc) (*BranchTask, []TaskInterface) {
+	bt := NewBranchTask("branch", choose)
+	fast := newMockTask("fast", pb.TaskState_SUCCESS, nil)
+	slow := newMockTask("slow", pb.TaskState_FAILED, nil)
+	bt.Add(fast)
+	bt.Add(slow)
+
+	return bt, []TaskInterface{fast, slow, bt}
+}
+
+func TestBranchTask(t *testing.T) {
+	ctx := context.Background()
+	root := NewNestedTask("root", NestedTaskOptions{})
+	check := newMockTask("check", pb.TaskState_SUCCESS, nil)
+	check.SetOutput("mode", "slow")
+	bt, tasks := newBranchTestTask(func(ctx context.Context, task *Task) (string, error) {
+		v, err := task.OutputOf([]string{"..", "check"}, "mode")
+		return v.GetStringValue(), err
+	})
+	root.Add(check)
+	root.Add(bt)
+
+	bt.SetState(pb.TaskState_RUNNING)
+	bt.Poll(ctx)
+
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_SKIPPED, pb.TaskState_FAILED, pb.TaskState_FAILED})
+	if v, err := bt.Output(BranchOutput); err != nil || v.GetStringValue() != "slow" {
+		t.Errorf("expecting the chosen branch to be published, got %v (%v)", v, err)
+	}
+
+	// The branch is chosen again when the task is rerun.
+	check.SetOutput("mode", "fast")
+	bt.SetState(pb.TaskState_PENDING)
+	bt.SetState(pb.TaskState_RUNNING)
+	bt.Poll(ctx)
+
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_SUCCESS, pb.TaskState_SKIPPED, pb.TaskState_SUCCESS})
+}
+
+func TestBranchTask_NoBranch(t *testing.T) {
+	bt, tasks := newBranchTestTask(func(ctx context.Context, task *Task) (string, error) {
+		return "", nil
+	})
+
+	bt.SetState(pb.TaskState_RUNNING)
+	bt.Poll(context.Background())
+
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_SKIPPED, pb.TaskState_SKIPPED, pb.TaskState_SUCCESS})
+}
+
+func TestBranchTask_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		choose BranchFunc
+	}{
+		{"error", func(ctx context.Context, task *Task) (string, error) { return "", errors.New("boom") }},
+		{"unknown branch", func(ctx context.Context, task *Task) (string, error) { return "nope", nil }},
+	} {
+		bt, tasks := newBranchTestTask(tt.choose)
+
+		bt.SetState(pb.TaskState_RUNNING)
+		bt.Poll(context.Background())
+
+		if msg := bt.Proto(nil).Message; !strings.HasPrefix(msg, "choosing a branch failed") {
+			t.Errorf("%s: unexpected message %q", tt.name, msg)
+		}
+		compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_PENDING, pb.TaskState_PENDING, pb.TaskState_FAILED})
+	}
+}
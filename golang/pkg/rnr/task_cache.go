@@ -0,0 +1,116 @@
+package rnr
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+	proto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Every task caches an immutable protobuf snapshot of its subtree. Any change to a task marks the task and all its
+// ancestors dirty, so that only the changed paths of the tree get rebuilt and unchanged subtrees are shared between
+// the snapshots.
+//
+// Invariant: a dirty task's ancestors are dirty as well. A task clears its flag before rebuilding its snapshot, so
+// any change racing with the rebuild marks the task dirty again.
+//
+// The fields which change without the task being changed, i.e. `last_polled` and `duration`, aren't part of the
+// snapshots; they're attached to the copies returned by Proto.
+
+// snapshotter provides the cached snapshots described above.
+type snapshotter interface {
+	snapshot() *pb.Task
+	attachVolatile(*pb.Task, time.Time)
+	setParent(*Task)
+}
+
+// taskProto returns a read-only protobuf of any task, avoiding a copy when possible.
+func taskProto(task TaskInterface) *pb.Task {
+	if t, ok := task.(snapshotter); ok {
+		return t.snapshot()
+	}
+
+	return task.Proto(nil)
+}
+
+// setParent sets the task to notify about the changes of this task; nil detaches the task from its parent.
+func (task *Task) setParent(parent *Task) {
+	task.parent.Store(parent)
+}
+
+func (task *Task) getParent() *Task {
+	parent, _ := task.parent.Load().(*Task)
+	return parent
+}
+
+// markDirty invalidates the cached snapshots of the task and all its ancestors.
+func (task *Task) markDirty() {
+	for t := task; t != nil; t = t.getParent() {
+		if !atomic.CompareAndSwapInt32(&t.dirty, 0, 1) {
+			// Already dirty, and so are the ancestors.
+			return
+		}
+	}
+}
+
+// snapshot returns the task's protobuf including the children, rebuilding it only if anything has changed since the
+// last call. The returned protobuf is shared and must not be modified.
+func (task *Task) snapshot() *pb.Task {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+
+	if task.cache != nil && atomic.LoadInt32(&task.dirty) == 0 {
+		return task.cache
+	}
+	atomic.StoreInt32(&task.dirty, 0)
+
+	ret, ok := proto.Clone(task.pb).(*pb.Task)
+	if !ok {
+		log.Fatalf("Failed to clone proto")
+	}
+
+	volatile := false
+	ret.Children = make([]*pb.Task, len(task.children))
+	for i, c := range task.children {
+		if s, ok := c.(snapshotter); ok {
+			ret.Children[i] = s.snapshot()
+		} else {
+			// Custom task types can't notify us about their changes.
+			ret.Children[i] = c.Proto(nil)
+			volatile = true
+		}
+	}
+	if volatile {
+		atomic.StoreInt32(&task.dirty, 1)
+	}
+
+	task.cache = ret
+
+	return ret
+}
+
+// attachVolatile sets the fields left out of the snapshots in `p`, a copy of the task's snapshot, and its children.
+func (task *Task) attachVolatile(p *pb.Task, now time.Time) {
+	task.mu.Lock()
+	lastPolled := task.lastPolled
+	children := task.children
+	task.mu.Unlock()
+
+	if !lastPolled.IsZero() {
+		p.LastPolled = timestamppb.New(lastPolled)
+	}
+	p.Duration = taskDuration(p, now)
+
+	for i, cp := range p.Children {
+		// The children might have changed since the snapshot was taken.
+		if i >= len(children) || children[i].Name() != cp.Name {
+			continue
+		}
+		if s, ok := children[i].(snapshotter); ok {
+			s.attachVolatile(cp, now)
+		}
+	}
+}
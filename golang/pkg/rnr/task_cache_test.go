@@ -0,0 +1,130 @@
+package rnr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+func TestTask_SnapshotReusesUnchangedSubtrees(t *testing.T) {
+	root := NewNestedTask("root", NestedTaskOptions{})
+	a := NewNestedTask("a", NestedTaskOptions{})
+	b := NewNestedTask("b", NestedTaskOptions{})
+	leaf := newMockTask("leaf", pb.TaskState_SUCCESS, nil)
+	root.Add(a)
+	root.Add(b)
+	b.Add(leaf)
+
+	old := root.snapshot()
+	if root.snapshot() != old {
+		t.Fatalf("expected an unchanged tree to be served from the cache")
+	}
+
+	leaf.SetState(pb.TaskState_RUNNING)
+	new := root.snapshot()
+
+	if new == old {
+		t.Fatalf("expected a change in a leaf to invalidate the root")
+	}
+	if new.Children[0] != old.Children[0] {
+		t.Errorf("expected the unchanged subtree to be reused")
+	}
+	if new.Children[1] == old.Children[1] {
+		t.Errorf("expected the changed subtree to be rebuilt")
+	}
+	if got := new.Children[1].Children[0].State; got != pb.TaskState_RUNNING {
+		t.Errorf("expected the leaf to be RUNNING, got %v", got)
+	}
+	if got := old.Children[1].Children[0].State; got != pb.TaskState_PENDING {
+		t.Errorf("expected the old snapshot to stay intact, got %v", got)
+	}
+}
+
+func TestTask_SnapshotTracksChildren(t *testing.T) {
+	root := NewNestedTask("root", NestedTaskOptions{})
+	root.Add(newMockTask("a", pb.TaskState_SUCCESS, nil))
+	root.snapshot()
+
+	root.Add(newMockTask("b", pb.TaskState_SUCCESS, nil))
+	if got := len(root.snapshot().Children); got != 2 {
+		t.Fatalf("expected 2 children after Add, got %d", got)
+	}
+
+	removed := root.GetChild("a")
+	root.Remove("a")
+	if got := len(root.snapshot().Children); got != 1 {
+		t.Fatalf("expected 1 child after Remove, got %d", got)
+	}
+
+	// The removed task doesn't invalidate its former parent anymore.
+	old := root.snapshot()
+	removed.Proto(func(p *pb.Task) *pb.Task {
+		p.Message = "detached"
+		return p
+	})
+	if root.snapshot() != old {
+		t.Errorf("expected a removed child not to invalidate its former parent")
+	}
+}
+
+func TestTask_ProtoIsOwnedByCaller(t *testing.T) {
+	root := NewNestedTask("root", NestedTaskOptions{})
+	root.Add(newMockTask("a", pb.TaskState_SUCCESS, nil))
+
+	p := root.Proto(nil)
+	p.Children[0].Message = "modified"
+	p.Children = nil
+
+	snap := root.snapshot()
+	if len(snap.Children) != 1 || snap.Children[0].Message != "" {
+		t.Errorf("expected modifications of Proto's result not to affect the task, got %v", snap)
+	}
+}
+
+func TestJob_PollReusesUnchangedSubtrees(t *testing.T) {
+	ctx := context.Background()
+	root := NewNestedTask("root", NestedTaskOptions{Parallelism: 2})
+	done := NewNestedTask("done", NestedTaskOptions{})
+	done.Add(newMockTask("leaf", pb.TaskState_SUCCESS, nil))
+	running := newMockTask("running", pb.TaskState_RUNNING, nil)
+	root.Add(done)
+	root.Add(running)
+	root.SetState(pb.TaskState_RUNNING)
+	j := NewJob(root)
+
+	for i := 0; i < 3; i++ {
+		j.Poll(ctx)
+	}
+	compareTaskStates(t, []TaskInterface{done, running}, []pb.TaskState{pb.TaskState_SUCCESS, pb.TaskState_RUNNING})
+
+	// Polls which don't change anything keep the whole tree.
+	old := taskProto(root)
+	j.Poll(ctx)
+	if taskProto(root) != old {
+		t.Fatalf("expected polls without changes to keep the snapshot")
+	}
+
+	running.SetPriority(1)
+	j.Poll(ctx)
+	new := taskProto(root)
+	if new == old || new.Children[1] == old.Children[1] {
+		t.Fatalf("expected the changed subtree to be rebuilt")
+	}
+	if new.Children[0] != old.Children[0] {
+		t.Errorf("expected the finished subtree to be reused")
+	}
+
+	// The volatile fields are current, even though the snapshot isn't rebuilt.
+	polled := time.Now()
+	j.Poll(ctx)
+	p := running.Proto(nil)
+	if got := p.LastPolled.AsTime(); got.Before(polled) {
+		t.Errorf("expected last_polled to be after %v, got %v", polled, got)
+	}
+	time.Sleep(tick)
+	if d := j.Proto(nil).Root.Children[1].Duration.AsDuration(); d < p.Duration.AsDuration()+tick {
+		t.Errorf("expected the duration to grow from %v by %v, got %v", p.Duration.AsDuration(), tick, d)
+	}
+}
@@ -0,0 +1,51 @@
+package rnr
+
+import (
+	"context"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+// isActive returns whether the task's context should be kept alive in `state`.
+func (task *Task) isActive(state pb.TaskState) bool {
+	if task.activeIn != nil {
+		return task.activeIn(state)
+	}
+
+	return taskSchedState(&pb.Task{State: state}) == RUNNING
+}
+
+// context returns the task's context, derived from `parent`. The context is created once the task becomes active and
+// is cancelled as soon as it stops being active, is cancelled or removed from its parent. The context of an inactive
+// task is always cancelled.
+func (task *Task) context(parent context.Context) context.Context {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+
+	if !task.isActive(task.pb.State) {
+		ctx, cancel := context.WithCancel(parent)
+		cancel()
+		return ctx
+	}
+
+	// Recreate the context if the parent's one was cancelled in the meantime
+	if task.runCtx == nil || task.runCtx.Err() != nil {
+		task.cancelContext()
+		if at, _, _, ok := task.expiry(); ok {
+			task.runCtx, task.runCancel = context.WithDeadline(parent, at)
+		} else {
+			task.runCtx, task.runCancel = context.WithCancel(parent)
+		}
+	}
+
+	return task.runCtx
+}
+
+// cancelContext cancels the task's context, if any. Must be called with `task.mu` held.
+func (task *Task) cancelContext() {
+	if task.runCancel != nil {
+		task.runCancel()
+	}
+	task.runCtx = nil
+	task.runCancel = nil
+}
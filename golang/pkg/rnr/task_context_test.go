@@ -0,0 +1,90 @@
+package rnr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+// newContextTask returns a task storing the context it was last polled with.
+func newContextTask(name string, ctx *context.Context) *Task {
+	return NewTask(name, false, func(c context.Context, task *Task) {
+		*ctx = c
+	})
+}
+
+func TestTask_ContextCancelledWhenDone(t *testing.T) {
+	var ctx context.Context
+	task := newContextTask("task", &ctx)
+
+	task.Poll(context.Background())
+	if ctx.Err() == nil {
+		t.Errorf("expecting a pending task to get a cancelled context")
+	}
+
+	task.SetState(pb.TaskState_RUNNING)
+	task.Poll(context.Background())
+	runCtx := ctx
+	if runCtx.Err() != nil {
+		t.Fatalf("expecting a running task to get a live context, got %v", runCtx.Err())
+	}
+
+	task.Poll(context.Background())
+	if ctx != runCtx {
+		t.Errorf("expecting the context to be kept while the task is running")
+	}
+
+	j := NewJob(task)
+	if err := j.TaskRequest(&pb.TaskRequest{State: pb.TaskState_SKIPPED}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runCtx.Err() == nil {
+		t.Errorf("expecting the context to be cancelled once the task is done")
+	}
+}
+
+func TestTask_ContextCancelledWithParent(t *testing.T) {
+	var ctx context.Context
+	nt := NewNestedTask("nested", NestedTaskOptions{})
+	nt.Add(newContextTask("child", &ctx))
+	nt.SetState(pb.TaskState_RUNNING)
+
+	nt.Poll(context.Background())
+	if ctx.Err() != nil {
+		t.Fatalf("expecting a running task to get a live context, got %v", ctx.Err())
+	}
+
+	// "Pause" the parent
+	nt.SetState(pb.TaskState_PENDING)
+	if ctx.Err() == nil {
+		t.Errorf("expecting the context to be cancelled together with the parent's one")
+	}
+
+	nt.SetState(pb.TaskState_RUNNING)
+	nt.Poll(context.Background())
+	if ctx.Err() != nil {
+		t.Errorf("expecting a fresh context once the parent resumes, got %v", ctx.Err())
+	}
+}
+
+func TestTask_ContextCancelledOnRemove(t *testing.T) {
+	var ctx context.Context
+	nt := NewNestedTask("nested", NestedTaskOptions{})
+	nt.Add(newContextTask("child", &ctx))
+	nt.SetState(pb.TaskState_RUNNING)
+	nt.Poll(context.Background())
+
+	if err := nt.Remove("child"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Errorf("expecting the context to be cancelled once the task is removed")
+	}
+	if c := nt.GetChild("child"); c != nil {
+		t.Errorf("expecting the child to be removed, got %v", c)
+	}
+	if err := nt.Remove("child"); err == nil {
+		t.Errorf("expecting an error when removing a non-existent child")
+	}
+}
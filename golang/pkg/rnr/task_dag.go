@@ -0,0 +1,261 @@
+package rnr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+// ErrDependencyCycle is returned when a dependency would make the graph of a DAGTask's children cyclic.
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+type DAGTaskOptions struct {
+	Parallelism int // the maximum number of children running at once; 0 means unlimited.
+}
+
+// DAGTask runs its children according to the dependencies between them. A child starts once all its dependencies
+// have succeeded, and it's skipped once any of them fails or is skipped. The DAGTask succeeds once all its children
+// are done, unless any of them has failed.
+//
+// The dependencies of each child are published in its protobuf as `depends_on`.
+type DAGTask struct {
+	*Task
+	opts   DAGTaskOptions
+	depsMu sync.Mutex
+	deps   map[string][]string // child name -> names of the children it depends on
+	polls  childPolls
+}
+
+func NewDAGTask(name string, opts DAGTaskOptions) *DAGTask {
+	if opts.Parallelism < 0 {
+		opts.Parallelism = 0
+	}
+
+	ret := &DAGTask{
+		opts: opts,
+		deps: map[string][]string{},
+	}
+	ret.Task = NewTask(name, true, ret.poll)
+
+	return ret
+}
+
+// Add adds a child depending on the already added children named `dependsOn`.
+func (dt *DAGTask) Add(task TaskInterface, dependsOn ...string) error {
+	for _, dep := range dependsOn {
+		if dt.GetChild(dep) == nil {
+			return fmt.Errorf("%w: dependency '%s' of '%s'", ErrTaskNotFound, dep, task.Name())
+		}
+	}
+
+	if err := dt.Task.Add(task); err != nil {
+		return err
+	}
+
+	return dt.DependsOn(task.Name(), dependsOn...)
+}
+
+// DependsOn adds dependencies of the child `name` on the children named `dependsOn`. Dependencies that would make the
+// graph cyclic are rejected with ErrDependencyCycle.
+func (dt *DAGTask) DependsOn(name string, dependsOn ...string) error {
+	child := dt.GetChild(name)
+	if child == nil {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, name)
+	}
+	for _, dep := range dependsOn {
+		if dt.GetChild(dep) == nil {
+			return fmt.Errorf("%w: dependency '%s' of '%s'", ErrTaskNotFound, dep, name)
+		}
+	}
+
+	dt.depsMu.Lock()
+	deps := append([]string{}, dt.deps[name]...)
+	for _, dep := range dependsOn {
+		if !containsString(deps, dep) {
+			deps = append(deps, dep)
+		}
+	}
+	visited := map[string]bool{}
+	for _, dep := range dependsOn {
+		if cycle := dt.findPath(dep, name, visited); cycle != nil {
+			dt.depsMu.Unlock()
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(append([]string{name}, cycle...), " -> "))
+		}
+	}
+	dt.deps[name] = deps
+	dt.depsMu.Unlock()
+
+	updateProto(child, pb.TransitionSource_SOURCE_SCHEDULER, func(p *pb.Task) *pb.Task {
+		p.DependsOn = deps
+		return p
+	})
+
+	return nil
+}
+
+// findPath returns the path of dependencies leading from `from` to `to`, or nil if there's none. `visited` collects
+// the children already searched, which don't lead to `to`, so that each child is searched at most once. It must be
+// called with `depsMu` held.
+func (dt *DAGTask) findPath(from, to string, visited map[string]bool) []string {
+	if from == to {
+		return []string{to}
+	}
+	if visited[from] {
+		return nil
+	}
+	visited[from] = true
+
+	for _, dep := range dt.deps[from] {
+		if p := dt.findPath(dep, to, visited); p != nil {
+			return append([]string{from}, p...)
+		}
+	}
+
+	return nil
+}
+
+// Dependencies returns the names of the children the child `name` depends on.
+func (dt *DAGTask) Dependencies(name string) []string {
+	dt.depsMu.Lock()
+	defer dt.depsMu.Unlock()
+
+	return append([]string{}, dt.deps[name]...)
+}
+
+// Remove removes the child with the specified name, along with all the dependencies on it.
+func (dt *DAGTask) Remove(name string) error {
+	if err := dt.Task.Remove(name); err != nil {
+		return err
+	}
+
+	dt.depsMu.Lock()
+	delete(dt.deps, name)
+	changed := map[string][]string{}
+	for child, deps := range dt.deps {
+		if !containsString(deps, name) {
+			continue
+		}
+		var kept []string
+		for _, dep := range deps {
+			if dep != name {
+				kept = append(kept, dep)
+			}
+		}
+		dt.deps[child] = kept
+		changed[child] = kept
+	}
+	dt.depsMu.Unlock()
+
+	for child, deps := range changed {
+		if c := dt.GetChild(child); c != nil {
+			deps := deps
+			updateProto(c, pb.TransitionSource_SOURCE_SCHEDULER, func(p *pb.Task) *pb.Task {
+				p.DependsOn = deps
+				return p
+			})
+		}
+	}
+
+	return nil
+}
+
+func (dt *DAGTask) poll(ctx context.Context, task *Task) {
+	if taskSchedState(task.snapshot()) != RUNNING {
+		return
+	}
+
+	children := task.Children()
+	states := make(map[string]pb.TaskState, len(children))
+	running := 0
+	for _, child := range children {
+		state := taskProto(child).State
+		states[child.Name()] = state
+		if sched := taskSchedState(&pb.Task{State: state}); sched == RUNNING || sched == PAUSED {
+			running++
+		}
+	}
+
+	// Start the children whose dependencies have succeeded and skip those with a failed dependency.
+	for _, child := range children {
+		name := child.Name()
+		if taskSchedState(&pb.Task{State: states[name]}) != PENDING {
+			continue
+		}
+
+		ready := true
+		var failed string
+		for _, dep := range dt.Dependencies(name) {
+			switch states[dep] {
+			case pb.TaskState_SUCCESS:
+			case pb.TaskState_FAILED, pb.TaskState_SKIPPED:
+				failed = dep
+			default:
+				ready = false
+			}
+			if failed != "" {
+				break
+			}
+		}
+
+		switch {
+		case failed != "":
+			updateProto(child, pb.TransitionSource_SOURCE_SCHEDULER, func(p *pb.Task) *pb.Task {
+				p.State = pb.TaskState_SKIPPED
+				p.Message = fmt.Sprintf("dependency '%s' is %s", failed, states[failed])
+				return p
+			})
+			states[name] = pb.TaskState_SKIPPED
+
+		case ready && (dt.opts.Parallelism == 0 || running < dt.opts.Parallelism) && acquirePools(ctx, child, taskProto(child)):
+			setState(child, pb.TaskState_RUNNING, pb.TransitionSource_SOURCE_SCHEDULER)
+			states[name] = pb.TaskState_RUNNING
+			running++
+		}
+	}
+
+	// Poll the child tasks
+	dt.polls.poll(ctx, children)
+
+	successCount := 0
+	failedCount := 0
+	doneCount := 0
+	for _, child := range children {
+		cpb := taskProto(child)
+		if cpb.State == pb.TaskState_SUCCESS {
+			successCount++
+		} else if cpb.State == pb.TaskState_FAILED {
+			failedCount++
+		}
+
+		if taskSchedState(cpb) == DONE {
+			doneCount++
+		}
+	}
+
+	task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(pb *pb.Task) *pb.Task {
+		pb.Message = fmt.Sprintf("%d/%d", successCount, len(children))
+		return pb
+	})
+
+	if doneCount == len(children) {
+		if failedCount > 0 {
+			task.SetState(pb.TaskState_FAILED)
+		} else {
+			task.SetState(pb.TaskState_SUCCESS)
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+
+	return false
+}
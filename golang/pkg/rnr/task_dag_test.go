@@ -0,0 +1,141 @@
+package rnr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+func TestDAGTask_Diamond(t *testing.T) {
+	ctx := context.Background()
+	dt := NewDAGTask("dag", DAGTaskOptions{})
+	a := newMockTask("a", pb.TaskState_SUCCESS, nil)
+	b := newMockTask("b", pb.TaskState_SUCCESS, nil)
+	c := newMockTask("c", pb.TaskState_SUCCESS, nil)
+	d := newMockTask("d", pb.TaskState_SUCCESS, nil)
+
+	for _, err := range []error{dt.Add(a), dt.Add(b, "a"), dt.Add(c, "a"), dt.Add(d, "b", "c")} {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tasks := []TaskInterface{a, b, c, d, dt}
+	dt.SetState(pb.TaskState_RUNNING)
+
+	dt.Poll(ctx)
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_SUCCESS, pb.TaskState_PENDING, pb.TaskState_PENDING, pb.TaskState_PENDING, pb.TaskState_RUNNING})
+
+	dt.Poll(ctx)
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_SUCCESS, pb.TaskState_SUCCESS, pb.TaskState_SUCCESS, pb.TaskState_PENDING, pb.TaskState_RUNNING})
+
+	dt.Poll(ctx)
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_SUCCESS, pb.TaskState_SUCCESS, pb.TaskState_SUCCESS, pb.TaskState_SUCCESS, pb.TaskState_SUCCESS})
+
+	if deps := d.Proto(nil).DependsOn; fmt.Sprint(deps) != "[b c]" {
+		t.Errorf("expecting the dependencies of d to be published, got %v", deps)
+	}
+}
+
+func TestDAGTask_SkipDependents(t *testing.T) {
+	ctx := context.Background()
+	dt := NewDAGTask("dag", DAGTaskOptions{})
+	a := newMockTask("a", pb.TaskState_FAILED, nil)
+	b := newMockTask("b", pb.TaskState_SUCCESS, nil)
+	c := newMockTask("c", pb.TaskState_SUCCESS, nil)
+	other := newMockTask("other", pb.TaskState_SUCCESS, nil)
+	dt.Add(a)
+	dt.Add(b, "a")
+	dt.Add(c, "b")
+	dt.Add(other)
+
+	tasks := []TaskInterface{a, b, c, other, dt}
+	dt.SetState(pb.TaskState_RUNNING)
+
+	for i := 0; i < 5; i++ {
+		dt.Poll(ctx)
+	}
+
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_FAILED, pb.TaskState_SKIPPED, pb.TaskState_SKIPPED, pb.TaskState_SUCCESS, pb.TaskState_FAILED})
+	if msg := c.Proto(nil).Message; msg != "dependency 'b' is SKIPPED" {
+		t.Errorf("unexpected message of a skipped task: %q", msg)
+	}
+}
+
+func TestDAGTask_Parallelism(t *testing.T) {
+	dt := NewDAGTask("dag", DAGTaskOptions{Parallelism: 2})
+	var tasks []TaskInterface
+	for i := 0; i < 3; i++ {
+		task := newMockTask(fmt.Sprintf("task %d", i), pb.TaskState_RUNNING, nil)
+		dt.Add(task)
+		tasks = append(tasks, task)
+	}
+
+	dt.SetState(pb.TaskState_RUNNING)
+	dt.Poll(context.Background())
+
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_RUNNING, pb.TaskState_RUNNING, pb.TaskState_PENDING})
+}
+
+func TestDAGTask_DependencyErrors(t *testing.T) {
+	dt := NewDAGTask("dag", DAGTaskOptions{})
+	dt.Add(newMockTask("a", pb.TaskState_SUCCESS, nil))
+	dt.Add(newMockTask("b", pb.TaskState_SUCCESS, nil), "a")
+	dt.Add(newMockTask("c", pb.TaskState_SUCCESS, nil), "b")
+
+	if err := dt.Add(newMockTask("d", pb.TaskState_SUCCESS, nil), "nope"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("expecting ErrTaskNotFound for a missing dependency, got %v", err)
+	}
+	if dt.GetChild("d") != nil {
+		t.Errorf("expecting a child with a missing dependency not to be added")
+	}
+
+	for _, tt := range []struct{ name, dep string }{{"a", "c"}, {"a", "a"}, {"b", "c"}} {
+		if err := dt.DependsOn(tt.name, tt.dep); !errors.Is(err, ErrDependencyCycle) {
+			t.Errorf("expecting ErrDependencyCycle for %s -> %s, got %v", tt.name, tt.dep, err)
+		}
+	}
+	if deps := dt.Dependencies("a"); len(deps) != 0 {
+		t.Errorf("expecting rejected dependencies not to be added, got %v", deps)
+	}
+
+	if err := dt.DependsOn("c", "a"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := dt.Remove("b"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deps := dt.Dependencies("c"); fmt.Sprint(deps) != "[a]" {
+		t.Errorf("expecting the dependency on a removed task to be dropped, got %v", deps)
+	}
+	if deps := dt.GetChild("c").Proto(nil).DependsOn; fmt.Sprint(deps) != "[a]" {
+		t.Errorf("expecting the proto to be updated, got %v", deps)
+	}
+}
+
+func TestDAGTask_DependencyCycleManyPaths(t *testing.T) {
+	// Each layer depends on both children of the previous one, so there are 2^n paths from the top to the bottom.
+	dt := NewDAGTask("dag", DAGTaskOptions{})
+	dt.Add(newMockTask("bottom", pb.TaskState_SUCCESS, nil))
+	prev := []string{"bottom", "bottom"}
+	for i := 0; i < 50; i++ {
+		layer := []string{fmt.Sprintf("l%d", i), fmt.Sprintf("r%d", i)}
+		for _, name := range layer {
+			if err := dt.Add(newMockTask(name, pb.TaskState_SUCCESS, nil), prev...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		prev = layer
+	}
+	dt.Add(newMockTask("top", pb.TaskState_SUCCESS, nil))
+
+	if err := dt.DependsOn("top", prev...); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := dt.DependsOn("bottom", "top"); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("expecting ErrDependencyCycle, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package rnr
+
+import (
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// timeNow returns the current time; tests may override it.
+var timeNow = time.Now
+
+// taskHistoryLimit is the maximum number of state transitions kept in a task's history.
+const taskHistoryLimit = 100
+
+// updateLifecycle maintains the lifecycle timestamps of a task whose state has changed from `old` to `task.State`.
+func updateLifecycle(old pb.TaskState, task *pb.Task, now time.Time) {
+	if old == task.State {
+		return
+	}
+	// Pausing and resuming a task doesn't restart it.
+	if old == pb.TaskState_PAUSED || task.State == pb.TaskState_PAUSED {
+		return
+	}
+
+	oldSched := taskSchedState(&pb.Task{State: old})
+
+	switch taskSchedState(task) {
+	case PENDING:
+		// The task was reset; it hasn't started yet.
+		task.Started = nil
+		task.Finished = nil
+
+	case RUNNING:
+		if oldSched != RUNNING {
+			task.Started = timestamppb.New(now)
+			task.Finished = nil
+		}
+
+	case DONE:
+		if oldSched != DONE {
+			task.Finished = timestamppb.New(now)
+		}
+	}
+}
+
+// taskDuration returns the time the task has spent running, or nil if it has never started.
+func taskDuration(task *pb.Task, now time.Time) *durationpb.Duration {
+	if task.Started == nil {
+		return nil
+	}
+
+	end := now
+	if task.Finished != nil {
+		end = task.Finished.AsTime()
+	}
+
+	return durationpb.New(end.Sub(task.Started.AsTime()))
+}
+
+// recordTransition appends the task's state change from `old` to `task.State` to its history.
+func recordTransition(old pb.TaskState, task *pb.Task, source pb.TransitionSource, now time.Time) {
+	if old == task.State {
+		return
+	}
+
+	task.History = append(task.History, &pb.StateTransition{
+		FromState: old,
+		ToState:   task.State,
+		Timestamp: timestamppb.New(now),
+		Message:   task.Message,
+		Source:    source,
+	})
+
+	if n := len(task.History); n > taskHistoryLimit {
+		task.History = task.History[n-taskHistoryLimit:]
+	}
+}
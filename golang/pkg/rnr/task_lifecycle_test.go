@@ -0,0 +1,130 @@
+package rnr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+func TestUpdateLifecycle(t *testing.T) {
+	t0 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	task := &pb.Task{State: pb.TaskState_PENDING}
+
+	task.State = pb.TaskState_RUNNING
+	updateLifecycle(pb.TaskState_PENDING, task, t0)
+	if task.Started == nil || !task.Started.AsTime().Equal(t0) {
+		t.Fatalf("expecting started to be %v, got %v", t0, task.Started)
+	}
+	if task.Finished != nil {
+		t.Fatalf("expecting finished to be unset, got %v", task.Finished)
+	}
+	if d := taskDuration(task, t0.Add(time.Minute)).AsDuration(); d != time.Minute {
+		t.Errorf("expecting duration of a running task to be %v, got %v", time.Minute, d)
+	}
+
+	// RUNNING -> ACTION_NEEDED doesn't restart the task
+	task.State = pb.TaskState_ACTION_NEEDED
+	updateLifecycle(pb.TaskState_RUNNING, task, t0.Add(time.Minute))
+	if !task.Started.AsTime().Equal(t0) {
+		t.Errorf("expecting started to stay %v, got %v", t0, task.Started.AsTime())
+	}
+
+	task.State = pb.TaskState_SUCCESS
+	updateLifecycle(pb.TaskState_ACTION_NEEDED, task, t0.Add(2*time.Minute))
+	if task.Finished == nil || !task.Finished.AsTime().Equal(t0.Add(2*time.Minute)) {
+		t.Fatalf("expecting finished to be %v, got %v", t0.Add(2*time.Minute), task.Finished)
+	}
+	if d := taskDuration(task, t0.Add(time.Hour)).AsDuration(); d != 2*time.Minute {
+		t.Errorf("expecting duration of a finished task to be %v, got %v", 2*time.Minute, d)
+	}
+
+	// Re-running a finished task starts it anew
+	task.State = pb.TaskState_RUNNING
+	updateLifecycle(pb.TaskState_SUCCESS, task, t0.Add(3*time.Minute))
+	if !task.Started.AsTime().Equal(t0.Add(3*time.Minute)) || task.Finished != nil {
+		t.Errorf("expecting restarted task to have fresh timestamps, got started=%v, finished=%v", task.Started, task.Finished)
+	}
+
+	task.State = pb.TaskState_PENDING
+	updateLifecycle(pb.TaskState_RUNNING, task, t0.Add(4*time.Minute))
+	if task.Started != nil || task.Finished != nil || taskDuration(task, t0) != nil {
+		t.Errorf("expecting reset task to have no timestamps, got started=%v, finished=%v", task.Started, task.Finished)
+	}
+}
+
+func TestTask_Timestamps(t *testing.T) {
+	task := newMockTask("mock", pb.TaskState_SUCCESS, nil)
+
+	p := task.Proto(nil)
+	if p.Created == nil {
+		t.Errorf("expecting created timestamp to be set")
+	}
+	if p.Started != nil || p.Finished != nil || p.LastPolled != nil || p.Duration != nil {
+		t.Errorf("expecting a new task to have no other timestamps, got %v", p)
+	}
+
+	task.SetState(pb.TaskState_RUNNING)
+	task.Poll(context.TODO())
+
+	p = task.Proto(nil)
+	if p.Started == nil || p.Finished == nil || p.LastPolled == nil || p.Duration == nil {
+		t.Errorf("expecting all timestamps to be set, got %v", p)
+	}
+}
+
+func TestTask_History(t *testing.T) {
+	ctx := context.TODO()
+	nt := NewNestedTask("root", NestedTaskOptions{})
+	ct := newMockTask("child", pb.TaskState_FAILED, nil)
+	nt.Add(ct)
+	j := NewJob(nt)
+
+	nt.SetState(pb.TaskState_RUNNING)
+	nt.Poll(ctx)
+
+	if err := j.TaskRequest(&pb.TaskRequest{Path: []string{"child"}, State: pb.TaskState_SKIPPED}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := []struct {
+		from, to pb.TaskState
+		source   pb.TransitionSource
+	}{
+		{pb.TaskState_PENDING, pb.TaskState_RUNNING, pb.TransitionSource_SOURCE_SCHEDULER},
+		{pb.TaskState_RUNNING, pb.TaskState_FAILED, pb.TransitionSource_SOURCE_TASK},
+		{pb.TaskState_FAILED, pb.TaskState_SKIPPED, pb.TransitionSource_SOURCE_REQUEST},
+	}
+
+	history := ct.Proto(nil).History
+	if len(history) != len(exp) {
+		t.Fatalf("expecting %d transitions, got %v", len(exp), history)
+	}
+	for i, e := range exp {
+		h := history[i]
+		if h.FromState != e.from || h.ToState != e.to || h.Source != e.source {
+			t.Errorf("expecting transition %d to be %v -> %v (%v), got %v -> %v (%v)", i, e.from, e.to, e.source, h.FromState, h.ToState, h.Source)
+		}
+		if h.Timestamp == nil {
+			t.Errorf("expecting transition %d to have a timestamp", i)
+		}
+	}
+}
+
+func TestTask_HistoryLimit(t *testing.T) {
+	task := newMockTask("mock", pb.TaskState_SUCCESS, nil)
+
+	for i := 0; i < taskHistoryLimit; i++ {
+		task.SetState(pb.TaskState_RUNNING)
+		task.SetState(pb.TaskState_PENDING)
+	}
+
+	history := task.Proto(nil).History
+	if len(history) != taskHistoryLimit {
+		t.Fatalf("expecting history to be capped at %d transitions, got %d", taskHistoryLimit, len(history))
+	}
+	if last := history[len(history)-1]; last.ToState != pb.TaskState_PENDING {
+		t.Errorf("expecting the most recent transition to be kept, got %v", last)
+	}
+}
@@ -0,0 +1,133 @@
+package rnr
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// taskLogLimit is the maximum number of log lines kept by a task.
+const taskLogLimit = 1000
+
+// LogSource is implemented by tasks keeping a log.
+type LogSource interface {
+	// Logs returns the lines logged since `seq` that are still kept by the task.
+	Logs(since uint64) *pb.TaskLogs
+	// LogsUpdated returns a channel that is closed once a new line is logged.
+	LogsUpdated() <-chan struct{}
+}
+
+// taskLog is a bounded ring buffer of log lines. It has its own lock, so that it can be written to from within
+// the task's callbacks.
+type taskLog struct {
+	mu      sync.Mutex
+	lines   []*pb.LogLine // a ring buffer of at most taskLogLimit lines
+	next    uint64        // the seq of the next line
+	updated chan struct{} // closed and replaced with each new line
+}
+
+// Logf appends a formatted line to the task's log; multi-line messages are split into several lines. It's safe to
+// call from any goroutine, including the task's callbacks.
+func (task *Task) Logf(format string, args ...interface{}) {
+	text := strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+	task.logs.append(strings.Split(text, "\n")...)
+}
+
+// Logs returns the lines logged since `seq` that are still kept by the task.
+func (task *Task) Logs(since uint64) *pb.TaskLogs {
+	return task.logs.since(since)
+}
+
+// LogsUpdated returns a channel that is closed once a new line is logged.
+func (task *Task) LogsUpdated() <-chan struct{} {
+	return task.logs.watch()
+}
+
+func (l *taskLog) append(lines ...string) {
+	now := timestamppb.New(timeNow())
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, text := range lines {
+		line := &pb.LogLine{Seq: l.next, Timestamp: now, Text: text}
+		if len(l.lines) < taskLogLimit {
+			l.lines = append(l.lines, line)
+		} else {
+			l.lines[l.next%taskLogLimit] = line
+		}
+		l.next++
+	}
+
+	if l.updated != nil {
+		close(l.updated)
+		l.updated = nil
+	}
+}
+
+func (l *taskLog) since(seq uint64) *pb.TaskLogs {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ret := &pb.TaskLogs{Next: l.next}
+
+	first := l.next - uint64(len(l.lines))
+	if seq < first {
+		seq = first
+	}
+	for ; seq < l.next; seq++ {
+		ret.Lines = append(ret.Lines, l.lines[seq%taskLogLimit])
+	}
+
+	return ret
+}
+
+func (l *taskLog) watch() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.updated == nil {
+		l.updated = make(chan struct{})
+	}
+
+	return l.updated
+}
+
+// logWriter is an io.Writer logging each complete line written to it to the task's log.
+type logWriter struct {
+	task *Task
+	mu   sync.Mutex
+	buf  []byte
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.task.logs.append(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush logs the incomplete last line, if any.
+func (w *logWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.task.logs.append(string(w.buf))
+		w.buf = nil
+	}
+}
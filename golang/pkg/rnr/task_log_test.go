@@ -0,0 +1,176 @@
+package rnr
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+func logTexts(logs *pb.TaskLogs) []string {
+	var ret []string
+	for _, l := range logs.Lines {
+		ret = append(ret, l.Text)
+	}
+	return ret
+}
+
+func TestTask_Logf(t *testing.T) {
+	task := NewTask("task", false, nil)
+
+	task.Logf("hello %s", "world")
+	task.Logf("two\nlines\n")
+
+	logs := task.Logs(0)
+	if got, want := fmt.Sprint(logTexts(logs)), "[hello world two lines]"; got != want {
+		t.Errorf("expecting lines %s, got %s", want, got)
+	}
+	if logs.Next != 3 {
+		t.Errorf("expecting next to be 3, got %d", logs.Next)
+	}
+	if got, want := fmt.Sprint(logTexts(task.Logs(2))), "[lines]"; got != want {
+		t.Errorf("expecting lines %s since 2, got %s", want, got)
+	}
+	if got := task.Logs(3).Lines; len(got) != 0 {
+		t.Errorf("expecting no lines since 3, got %v", got)
+	}
+}
+
+func TestTask_LogfRingBuffer(t *testing.T) {
+	task := NewTask("task", false, nil)
+
+	for i := 0; i < taskLogLimit+10; i++ {
+		task.Logf("line %d", i)
+	}
+
+	logs := task.Logs(0)
+	if len(logs.Lines) != taskLogLimit {
+		t.Fatalf("expecting %d lines, got %d", taskLogLimit, len(logs.Lines))
+	}
+	if first := logs.Lines[0]; first.Seq != 10 || first.Text != "line 10" {
+		t.Errorf("expecting the oldest lines to be dropped, got %v", first)
+	}
+	if last := logs.Lines[len(logs.Lines)-1]; last.Seq != taskLogLimit+9 {
+		t.Errorf("expecting the last line to be %d, got %v", taskLogLimit+9, last)
+	}
+}
+
+func TestTask_LogsUpdated(t *testing.T) {
+	task := NewTask("task", false, nil)
+	updated := task.LogsUpdated()
+
+	select {
+	case <-updated:
+		t.Fatalf("expecting no update before anything is logged")
+	default:
+	}
+
+	task.Logf("hello")
+
+	select {
+	case <-updated:
+	default:
+		t.Fatalf("expecting an update once a line is logged")
+	}
+}
+
+func TestShellTask_Logs(t *testing.T) {
+	st := NewShellTask("shell", "sh", "-c", "echo out; echo err >&2; printf partial")
+	st.SetState(pb.TaskState_RUNNING)
+
+	for i := 0; i < 500 && st.Proto(nil).State == pb.TaskState_RUNNING; i++ {
+		st.Poll(context.Background())
+		time.Sleep(tick)
+	}
+
+	if state := st.Proto(nil).State; state != pb.TaskState_SUCCESS {
+		t.Fatalf("expecting the command to succeed, got %v", state)
+	}
+	if got, want := fmt.Sprint(logTexts(st.Logs(0))), "[out err partial]"; got != want {
+		t.Errorf("expecting lines %s, got %s", want, got)
+	}
+}
+
+func TestWebserver_Logs(t *testing.T) {
+	root := NewNestedTask("root", NestedTaskOptions{})
+	child := NewCallbackTask("child", func(ctx context.Context, p *pb.Task) *pb.Task { return p })
+	root.Add(child)
+	ws := NewRnrWebserver(NewJob(root))
+
+	for i := 0; i < 5; i++ {
+		child.Logf("line %d", i)
+	}
+
+	for _, tc := range []struct {
+		query  string
+		status int
+		lines  string
+	}{
+		{"path=child", http.StatusOK, "[line 0 line 1 line 2 line 3 line 4]"},
+		{"path=child&since=3", http.StatusOK, "[line 3 line 4]"},
+		{"path=child&tail=2", http.StatusOK, "[line 3 line 4]"},
+		{"path=child&since=x", http.StatusBadRequest, ""},
+		{"path=nope", http.StatusNotFound, ""},
+	} {
+		rec := httptest.NewRecorder()
+		ws.logsHandler(rec, httptest.NewRequest(http.MethodGet, "/logs?"+tc.query, nil))
+
+		if rec.Code != tc.status {
+			t.Errorf("%s: expecting status %d, got %d", tc.query, tc.status, rec.Code)
+			continue
+		}
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		logs := &pb.TaskLogs{}
+		if err := jsonpb.Unmarshal(rec.Body, logs); err != nil {
+			t.Fatalf("%s: failed to parse the response: %v", tc.query, err)
+		}
+		if got := fmt.Sprint(logTexts(logs)); got != tc.lines {
+			t.Errorf("%s: expecting lines %s, got %s", tc.query, tc.lines, got)
+		}
+	}
+}
+
+func TestWebserver_LogsFollow(t *testing.T) {
+	task := NewCallbackTask("root", func(ctx context.Context, p *pb.Task) *pb.Task { return p })
+	srv := httptest.NewServer(http.HandlerFunc(NewRnrWebserver(NewJob(task)).logsHandler))
+	defer srv.Close()
+
+	task.Logf("before")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?follow=true&tail=1", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	go func() {
+		time.Sleep(tick)
+		task.Logf("after")
+	}()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for _, want := range []string{"before", "after"} {
+		if !scanner.Scan() {
+			t.Fatalf("expecting line %q, got %v", want, scanner.Err())
+		}
+		line := &pb.LogLine{}
+		if err := jsonpb.UnmarshalString(scanner.Text(), line); err != nil {
+			t.Fatalf("failed to parse line %q: %v", scanner.Text(), err)
+		}
+		if line.Text != want {
+			t.Errorf("expecting line %q, got %q", want, line.Text)
+		}
+	}
+}
@@ -3,105 +3,322 @@ package rnr
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/mplzik/rnr/golang/pkg/pb"
 )
 
 // Nested Task
 
-type NestedTaskCallback func(*Task, []*Task)
+type NestedTaskCallback func(*Task, []TaskInterface)
 
 type NestedTaskOptions struct {
 	CustomPoll  NestedTaskCallback // a callback called each time a Poll() on NestedTask is called.
 	Parallelism int                // the number of tasks to run in parallel; defaults to 1.
 	CompleteAll bool               // if `true`, the NestedTask will attempt to run all tasks before transitioning to either SUCCEEDED or FAILED state.
+
+	// Order, if set, is the order in which the pending children are started (and released by Waves) instead of the
+	// order they were added, e.g. ByPriority.
+	Order TaskOrder
+
+	// ConcurrencyKey, if set, groups the children, e.g. by the rack or the datacenter of the host they work on. At
+	// most ConcurrencyLimit children with the same key run at once (or the key's entry in ConcurrencyLimits, if any),
+	// besides the overall Parallelism; pending children whose key is saturated are passed over in favor of later ones.
+	// Children with an empty key are only limited by Parallelism.
+	ConcurrencyKey    func(*pb.Task) string
+	ConcurrencyLimit  int            // defaults to 1
+	ConcurrencyLimits map[string]int // limits of particular keys
+
+	// Waves, if set, make the NestedTask release its children gradually; see Wave.
+	Waves []Wave
+	// BakeTime is the time to wait after a wave is done before releasing the next one.
+	BakeTime time.Duration
+	// HealthCheck, if set, is called before releasing the next wave; an error fails the NestedTask. It's called from
+	// Poll, so it should return quickly.
+	HealthCheck func(ctx context.Context, task *Task) error
+
+	// MaxFailures and MaxFailurePercent set the failure budget: once more children than allowed by either of them
+	// fail, the NestedTask stops starting new children and moves to FailureState. While the failures stay within the
+	// budget, the NestedTask succeeds once all its children are done. If neither is set, CompleteAll applies instead.
+	MaxFailures       int
+	MaxFailurePercent float64
+	// CancelOnFailure makes the NestedTask cancel and skip its running children once the failure budget is exceeded.
+	CancelOnFailure bool
+	// FailureState is the state the NestedTask moves to once the failure budget is exceeded: FAILED (the default) or
+	// ACTION_NEEDED. In the latter case, the running children finish and setting the NestedTask back to RUNNING
+	// accepts the failures so far and continues.
+	FailureState pb.TaskState
 }
 
-func NewNestedTask(name string, opts NestedTaskOptions) *Task {
+// nestedFailures tracks the failure budget of a NestedTask.
+type nestedFailures struct {
+	started  time.Time // the start of the run the failures are tracked for
+	halted   bool      // whether the NestedTask is waiting for an operator after exceeding the budget
+	accepted int       // the number of failures accepted by an operator
+}
+
+// NestedTask schedules its children, running at most `Parallelism` of them at once.
+type NestedTask struct {
+	*Task
+	opts     NestedTaskOptions
+	waves    waveState
+	failures nestedFailures
+	polls    childPolls
+}
+
+func NewNestedTask(name string, opts NestedTaskOptions) *NestedTask {
 
 	// Sanitize opts
 	if opts.Parallelism < 1 {
 		opts.Parallelism = 1
 	}
+	if opts.ConcurrencyLimit < 1 {
+		opts.ConcurrencyLimit = 1
+	}
+	if opts.FailureState != pb.TaskState_ACTION_NEEDED {
+		opts.FailureState = pb.TaskState_FAILED
+	}
 
-	return NewTask(name, true, func(ctx context.Context, task *Task) {
-		// Begin insert
+	ret := &NestedTask{opts: opts}
+	ret.Task = NewTask(name, true, ret.poll)
 
-		if taskSchedState(task.Proto(nil)) != RUNNING {
+	return ret
+}
+
+func (nt *NestedTask) poll(ctx context.Context, task *Task) {
+	opts := nt.opts
+
+	tpb := task.snapshot()
+	if taskSchedState(tpb) != RUNNING {
+		return
+	}
+	halted := nt.trackFailures(tpb)
+
+	if opts.CustomPoll != nil {
+		opts.CustomPoll(task, task.Children())
+	}
+
+	// CustomPoll might have added some children; take a fresh snapshot.
+	children := task.Children()
+	ordered := orderTasks(children, opts.Order)
+
+	// Only the children released by the current wave can be started; all of them are released without waves.
+	var released map[string]bool
+	if halted {
+		released = map[string]bool{}
+	} else if len(opts.Waves) > 0 {
+		var ok bool
+		if released, ok = nt.releasedChildren(ctx, task, ordered); !ok {
 			return
 		}
+	}
+
+	running := 0
+	pending := []TaskInterface{}
+	runningByKey := map[string]int{}
+
+	// Perform scheduling
+	for i := range ordered {
+		child := ordered[i]
+		cpb := taskProto(child)
+		state := taskSchedState(cpb)
 
-		if opts.CustomPoll != nil {
-			opts.CustomPoll(task, task.children)
+		// Count running tasks; paused ones keep their slots.
+		if state == RUNNING || state == PAUSED {
+			running++
+			if key := nt.concurrencyKey(cpb); key != "" {
+				runningByKey[key]++
+			}
 		}
 
-		running := 0
-		pending := []*Task{}
+		if state == PENDING && (released == nil || released[cpb.Name]) {
+			pending = append(pending, child)
+		}
+	}
 
-		// Perform scheduling
-		for i := range task.children {
-			child := task.children[i]
-			state := taskSchedState(child.Proto(nil))
+	// Add more running tasks, if applicable. Don't try to stop tasks -- these have been likely invoked manually.
+	for _, child := range pending {
+		if running >= opts.Parallelism {
+			break
+		}
+		cpb := taskProto(child)
+		key := nt.concurrencyKey(cpb)
+		if key != "" && runningByKey[key] >= nt.concurrencyLimit(key) {
+			continue
+		}
+		// Only start the children which got the pool units they need.
+		if !acquirePools(ctx, child, cpb) {
+			continue
+		}
+		if key != "" {
+			runningByKey[key]++
+		}
+		setState(child, pb.TaskState_RUNNING, pb.TransitionSource_SOURCE_SCHEDULER)
+		running++
+	}
 
-			// Count running tasks
-			if state == RUNNING {
-				running++
-			}
+	// Poll the child tasks
+	nt.polls.poll(ctx, children)
 
-			if state == PENDING {
-				pending = append(pending, child)
-			}
+	successCount := 0
+	failedCount := 0
+	doneCount := 0
+	for _, child := range children {
+		cpb := taskProto(child)
+		if cpb.State == pb.TaskState_SUCCESS {
+			successCount++
+		} else if cpb.State == pb.TaskState_FAILED {
+			failedCount++
 		}
 
-		// Add more running tasks, if applicable. Don't try to stop tasks -- these have been likely invoked manually.
-		for (running < opts.Parallelism) && len(pending) > 0 {
-			// TODO: we shouldn't be mutating the PB this way; let's add a mutating function to the task interface.
-			pending[0].SetState(pb.TaskState_RUNNING)
-			pending = pending[1:]
-			running++
+		if taskSchedState(cpb) == DONE {
+			doneCount++
 		}
+	}
 
-		// Poll the child tasks
-		for _, child := range task.children {
-			child.Poll(ctx)
+	message := fmt.Sprintf("%d/%d", successCount, len(children))
+	if failedCount > 0 {
+		message += fmt.Sprintf(", %d failed", failedCount)
+	}
+	if len(opts.Waves) > 0 {
+		message += nt.waveStatus()
+	}
+	if halted {
+		message += ", waiting for an operator"
+	}
+	task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(pb *pb.Task) *pb.Task {
+		pb.Message = message
+		return pb
+	})
+
+	// Handle termination
+	if halted {
+		// Wait for the operator.
+		return
+	}
+
+	if nt.failureBudgetExceeded(failedCount, len(children)) {
+		nt.haltOnFailures(task, children, failedCount, message)
+		return
+	}
+
+	if doneCount == len(children) {
+		if successCount == len(children) || opts.hasFailureBudget() {
+			task.SetState(pb.TaskState_SUCCESS)
+		} else {
+			task.SetState(pb.TaskState_FAILED)
 		}
+	}
+}
 
-		successCount := 0
-		failedCount := 0
-		doneCount := 0
-		for _, child := range task.children {
-			cpb := child.Proto(nil)
-			if cpb.State == pb.TaskState_SUCCESS {
-				successCount++
-			} else if cpb.State == pb.TaskState_FAILED {
-				failedCount++
-			}
+// concurrencyKey returns the concurrency key of a child, or an empty string if it's not limited by any.
+func (nt *NestedTask) concurrencyKey(p *pb.Task) string {
+	if nt.opts.ConcurrencyKey == nil {
+		return ""
+	}
+
+	return nt.opts.ConcurrencyKey(p)
+}
 
-			if taskSchedState(cpb) == DONE {
-				doneCount++
+// concurrencyLimit returns the number of children with the concurrency key `key` allowed to run at once.
+func (nt *NestedTask) concurrencyLimit(key string) int {
+	if limit, ok := nt.opts.ConcurrencyLimits[key]; ok {
+		return limit
+	}
+
+	return nt.opts.ConcurrencyLimit
+}
+
+// trackFailures resets the failure budget if the NestedTask was rerun and accepts the failures so far if an operator
+// has resumed it after it was halted. It returns whether the NestedTask is still halted.
+func (nt *NestedTask) trackFailures(tpb *pb.Task) bool {
+	f := &nt.failures
+	if started := tpb.Started.AsTime(); !started.Equal(f.started) {
+		*f = nestedFailures{started: started}
+	}
+
+	if f.halted && tpb.State == pb.TaskState_RUNNING {
+		f.halted = false
+		f.accepted = 0
+		for _, child := range nt.Children() {
+			if taskProto(child).State == pb.TaskState_FAILED {
+				f.accepted++
 			}
 		}
+	}
 
-		task.Proto(func(pb *pb.Task) *pb.Task {
-			pb.Message = fmt.Sprintf("%d/%d", successCount, len(task.children))
-			return pb
-		})
+	return f.halted
+}
 
-		// Handle termination
-		if !opts.CompleteAll && failedCount > 0 {
-			// Fail everything on a first failed task.
-			task.SetState(pb.TaskState_FAILED)
-			return
-		}
+func (opts NestedTaskOptions) hasFailureBudget() bool {
+	return opts.MaxFailures > 0 || opts.MaxFailurePercent > 0
+}
+
+// failureBudgetExceeded returns whether `failed` out of `total` children is more than the options allow, not counting
+// the failures accepted by an operator.
+func (nt *NestedTask) failureBudgetExceeded(failed, total int) bool {
+	opts := nt.opts
+	failed -= nt.failures.accepted
+
+	if !opts.hasFailureBudget() {
+		return !opts.CompleteAll && failed > 0
+	}
+	if opts.MaxFailures > 0 && failed > opts.MaxFailures {
+		return true
+	}
+	if opts.MaxFailurePercent > 0 && float64(failed)*100 > opts.MaxFailurePercent*float64(total) {
+		return true
+	}
+
+	return false
+}
+
+// haltOnFailures moves the NestedTask to the FailureState, cancelling its running children if requested.
+func (nt *NestedTask) haltOnFailures(task *Task, children []TaskInterface, failed int, message string) {
+	opts := nt.opts
 
-		if doneCount == len(task.children) {
-			if successCount == len(task.children) {
-				task.SetState(pb.TaskState_SUCCESS)
-			} else {
-				task.SetState(pb.TaskState_FAILED)
+	if opts.hasFailureBudget() {
+		task.Logf("failure budget exceeded: %d of %d tasks failed", failed, len(children))
+		message += ", failure budget exceeded"
+	}
+
+	if opts.CancelOnFailure {
+		for _, child := range children {
+			if taskSchedState(taskProto(child)) != RUNNING {
+				continue
 			}
+			updateProto(child, pb.TransitionSource_SOURCE_SCHEDULER, func(p *pb.Task) *pb.Task {
+				p.State = pb.TaskState_SKIPPED
+				p.Message = "cancelled after too many failures"
+				return p
+			})
+			child.Cancel()
 		}
+	}
+
+	nt.failures.halted = opts.FailureState == pb.TaskState_ACTION_NEEDED
+	task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+		p.State = opts.FailureState
+		p.Message = message
+		return p
 	})
+}
 
-	// end insert
+// childPolls polls the children of a scheduler. A finished child is only polled once after each change, so that the
+// finished subtrees stay unchanged and their snapshots get reused. It's only used from the scheduler's poll.
+type childPolls struct {
+	polled map[TaskInterface]*pb.Task // child -> its snapshot right after the last poll
+}
+
+func (cp *childPolls) poll(ctx context.Context, children []TaskInterface) {
+	polled := make(map[TaskInterface]*pb.Task, len(children))
+	for _, child := range children {
+		cpb := taskProto(child)
+		if taskSchedState(cpb) != DONE || cp.polled[child] != cpb {
+			child.Poll(ctx)
+			cpb = taskProto(child)
+		}
+		polled[child] = cpb
+	}
+	cp.polled = polled
 }
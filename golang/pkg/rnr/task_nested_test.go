@@ -3,6 +3,7 @@ package rnr
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/mplzik/rnr/golang/pkg/pb"
@@ -64,12 +65,61 @@ func TestNestedTask_GetChild(t *testing.T) {
 	}
 }
 
+func TestNestedTask_RemoveKeepsOrder(t *testing.T) {
+	nt := NewNestedTask("nested task test", NestedTaskOptions{Parallelism: 1})
+	for i := 1; i <= 4; i++ {
+		nt.Add(newMockTask(fmt.Sprintf("child %d", i), pb.TaskState_SUCCESS, nil))
+	}
+
+	if err := nt.Remove("child 2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := nt.Add(newMockTask("child 2", pb.TaskState_SUCCESS, nil)); err != nil {
+		t.Fatalf("unexpected error when re-adding a removed child: %v", err)
+	}
+	if err := nt.Add(newMockTask("child 3", pb.TaskState_SUCCESS, nil)); err == nil {
+		t.Errorf("expecting an error when adding a duplicate child")
+	}
+
+	expected := []string{"child 1", "child 3", "child 4", "child 2"}
+	children := nt.Children()
+	if len(children) != len(expected) {
+		t.Fatalf("expecting %d children, got %d", len(expected), len(children))
+	}
+	for i, name := range expected {
+		if children[i].Name() != name {
+			t.Errorf("expecting child %d to be %q, got %q", i, name, children[i].Name())
+		}
+		if ct := nt.GetChild(name); ct != children[i] {
+			t.Errorf("expecting GetChild(%q) to return %v, got %v", name, children[i], ct)
+		}
+	}
+}
+
+func BenchmarkNestedTask_GetChild(b *testing.B) {
+	for _, n := range []int{10, 1000, 100000} {
+		nt := NewNestedTask("nested", NestedTaskOptions{})
+		for i := 0; i < n; i++ {
+			nt.Add(newMockTask(fmt.Sprintf("task: %6d", i), pb.TaskState_SUCCESS, nil))
+		}
+		last := fmt.Sprintf("task: %6d", n-1)
+
+		b.Run(fmt.Sprintf("NestedTask - %6d children", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if nt.GetChild(last) == nil {
+					b.Fatalf("child %q not found", last)
+				}
+			}
+		})
+	}
+}
+
 func TestNestedTask_FailFirst(t *testing.T) {
 	nt := NewNestedTask("nested task test", NestedTaskOptions{Parallelism: 1, CompleteAll: false})
 	ct1 := newMockTask("child 1", pb.TaskState_FAILED, nil)
 	ct2 := newMockTask("child 2", pb.TaskState_SUCCESS, nil)
 
-	tasks := []*Task{ct1, ct2, nt}
+	tasks := []TaskInterface{ct1, ct2, nt}
 
 	nt.Add(ct1)
 	nt.Add(ct2)
@@ -85,7 +135,7 @@ func TestNestedTask_CompleteAllFail(t *testing.T) {
 	ct1 := newMockTask("child 1", pb.TaskState_FAILED, nil)
 	ct2 := newMockTask("child 2", pb.TaskState_SUCCESS, nil)
 
-	tasks := []*Task{ct1, ct2, nt}
+	tasks := []TaskInterface{ct1, ct2, nt}
 
 	nt.Add(ct1)
 	nt.Add(ct2)
@@ -104,7 +154,7 @@ func TestNestedTask_CompleteAllSuccess(t *testing.T) {
 	ct2 := newMockTask("child 2", pb.TaskState_SUCCESS, nil)
 	nt := NewNestedTask("nested task test", NestedTaskOptions{Parallelism: 1, CompleteAll: true})
 
-	tasks := []*Task{ct1, ct2, nt}
+	tasks := []TaskInterface{ct1, ct2, nt}
 
 	nt.Add(ct1)
 	nt.Add(ct2)
@@ -123,7 +173,7 @@ func TestNestedTask_CallbackInvoked(t *testing.T) {
 	nt := NewNestedTask("nested task test", NestedTaskOptions{
 		Parallelism: 1,
 		CompleteAll: true,
-		CustomPoll: func(nt *Task, children []*Task) {
+		CustomPoll: func(nt *Task, children []TaskInterface) {
 			childName := fmt.Sprintf("callback-added child %d", childrenAdded)
 			nt.Add(newMockTask(childName, pb.TaskState_SUCCESS, nil))
 			childrenAdded += 1
@@ -155,7 +205,7 @@ func TestNestedTask_CallbackInvoked(t *testing.T) {
 
 func TestNextedTask_PollAfterStateChange(t *testing.T) {
 	ctx := context.TODO()
-	pollCount := 0
+	var pollCount int32
 	ct := newMockTask("child 1", pb.TaskState_RUNNING, &pollCount) // This will stay in RUNNING state unless state is changed externally
 	nt := NewNestedTask("nested task test", NestedTaskOptions{Parallelism: 1, CompleteAll: true})
 
@@ -194,3 +244,126 @@ func TestNextedTask_PollAfterStateChange(t *testing.T) {
 		t.Errorf("task was not polled when transitioning from SUCCESS to SKIPPED state")
 	}
 }
+
+func TestNestedTask_MaxFailures(t *testing.T) {
+	ctx := context.TODO()
+	nt := NewNestedTask("nested task test", NestedTaskOptions{Parallelism: 1, MaxFailures: 1})
+	ct1 := newMockTask("child 1", pb.TaskState_FAILED, nil)
+	ct2 := newMockTask("child 2", pb.TaskState_SUCCESS, nil)
+	ct3 := newMockTask("child 3", pb.TaskState_FAILED, nil)
+	ct4 := newMockTask("child 4", pb.TaskState_SUCCESS, nil)
+
+	tasks := []TaskInterface{ct1, ct2, ct3, ct4, nt}
+	for _, ct := range tasks[:4] {
+		nt.Add(ct)
+	}
+	nt.SetState(pb.TaskState_RUNNING)
+
+	// A single failure is within the budget.
+	nt.Poll(ctx)
+	nt.Poll(ctx)
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_FAILED, pb.TaskState_SUCCESS, pb.TaskState_PENDING, pb.TaskState_PENDING, pb.TaskState_RUNNING})
+	if got := nt.Proto(nil).Message; got != "1/4, 1 failed" {
+		t.Errorf("unexpected message %q", got)
+	}
+
+	nt.Poll(ctx)
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_FAILED, pb.TaskState_SUCCESS, pb.TaskState_FAILED, pb.TaskState_PENDING, pb.TaskState_FAILED})
+	if got := nt.Proto(nil).Message; got != "1/4, 2 failed, failure budget exceeded" {
+		t.Errorf("unexpected message %q", got)
+	}
+}
+
+func TestNestedTask_MaxFailurePercentSuccess(t *testing.T) {
+	ctx := context.TODO()
+	nt := NewNestedTask("nested task test", NestedTaskOptions{Parallelism: 2, MaxFailurePercent: 50})
+	ct1 := newMockTask("child 1", pb.TaskState_FAILED, nil)
+	ct2 := newMockTask("child 2", pb.TaskState_SUCCESS, nil)
+
+	nt.Add(ct1)
+	nt.Add(ct2)
+	nt.SetState(pb.TaskState_RUNNING)
+
+	// Failures within the budget don't fail the task.
+	nt.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{ct1, ct2, nt}, []pb.TaskState{pb.TaskState_FAILED, pb.TaskState_SUCCESS, pb.TaskState_SUCCESS})
+}
+
+func TestNestedTask_CancelOnFailure(t *testing.T) {
+	ctx := context.TODO()
+	nt := NewNestedTask("nested task test", NestedTaskOptions{Parallelism: 2, CancelOnFailure: true})
+	ct1 := newMockTask("child 1", pb.TaskState_RUNNING, nil)
+	ct2 := newMockTask("child 2", pb.TaskState_FAILED, nil)
+	ct3 := newMockTask("child 3", pb.TaskState_SUCCESS, nil)
+
+	tasks := []TaskInterface{ct1, ct2, ct3, nt}
+	nt.Add(ct1)
+	nt.Add(ct2)
+	nt.Add(ct3)
+	nt.SetState(pb.TaskState_RUNNING)
+
+	nt.Poll(ctx)
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_SKIPPED, pb.TaskState_FAILED, pb.TaskState_PENDING, pb.TaskState_FAILED})
+}
+
+func TestNestedTask_FailureActionNeeded(t *testing.T) {
+	ctx := context.TODO()
+	nt := NewNestedTask("nested task test", NestedTaskOptions{
+		Parallelism:  2,
+		MaxFailures:  1,
+		FailureState: pb.TaskState_ACTION_NEEDED,
+	})
+	ct1 := newMockTask("child 1", pb.TaskState_FAILED, nil)
+	ct2 := newMockTask("child 2", pb.TaskState_FAILED, nil)
+	ct3 := newMockTask("child 3", pb.TaskState_FAILED, nil)
+	ct4 := newMockTask("child 4", pb.TaskState_SUCCESS, nil)
+
+	tasks := []TaskInterface{ct1, ct2, ct3, ct4, nt}
+	for _, ct := range tasks[:4] {
+		nt.Add(ct)
+	}
+	nt.SetState(pb.TaskState_RUNNING)
+
+	nt.Poll(ctx)
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_FAILED, pb.TaskState_FAILED, pb.TaskState_PENDING, pb.TaskState_PENDING, pb.TaskState_ACTION_NEEDED})
+
+	// No new children are started while waiting for the operator.
+	nt.Poll(ctx)
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_FAILED, pb.TaskState_FAILED, pb.TaskState_PENDING, pb.TaskState_PENDING, pb.TaskState_ACTION_NEEDED})
+	if got := nt.Proto(nil).Message; got != "0/4, 2 failed, waiting for an operator" {
+		t.Errorf("unexpected message %q", got)
+	}
+
+	// Resuming accepts the failures so far; the next one is still within the budget.
+	nt.SetState(pb.TaskState_RUNNING)
+	nt.Poll(ctx)
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_FAILED, pb.TaskState_FAILED, pb.TaskState_FAILED, pb.TaskState_SUCCESS, pb.TaskState_SUCCESS})
+}
+
+func TestNestedTask_ConcurrencyKey(t *testing.T) {
+	ctx := context.TODO()
+	rack := func(p *pb.Task) string {
+		return strings.SplitN(p.Name, "/", 2)[0]
+	}
+
+	for _, tc := range []struct {
+		limits map[string]int
+		want   []pb.TaskState
+	}{
+		{nil, []pb.TaskState{pb.TaskState_RUNNING, pb.TaskState_PENDING, pb.TaskState_RUNNING, pb.TaskState_PENDING}},
+		{map[string]int{"rack1": 2}, []pb.TaskState{pb.TaskState_RUNNING, pb.TaskState_RUNNING, pb.TaskState_RUNNING, pb.TaskState_PENDING}},
+	} {
+		nt := NewNestedTask("nested task test", NestedTaskOptions{
+			Parallelism:       10,
+			ConcurrencyKey:    rack,
+			ConcurrencyLimits: tc.limits,
+		})
+		for _, name := range []string{"rack1/host1", "rack1/host2", "rack2/host1", "rack2/host2"} {
+			nt.Add(newMockTask(name, pb.TaskState_RUNNING, nil))
+		}
+		nt.SetState(pb.TaskState_RUNNING)
+
+		nt.Poll(ctx)
+		compareTaskStates(t, nt.Children(), tc.want)
+	}
+}
@@ -0,0 +1,109 @@
+package rnr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+	proto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Outputs are key/value results published by tasks in their protobuf, so that the downstream tasks (and operators)
+// can consume them. The values are JSON-like: nil, bools, numbers, strings, lists and maps of them.
+
+// ErrOutputNotFound is returned when the requested output hasn't been set by the task.
+var ErrOutputNotFound = errors.New("output not found")
+
+// ParentPath is the path segment referring to the parent task in the paths resolved by Lookup.
+const ParentPath = ".."
+
+// SetOutput publishes an output of the task. Besides the types supported by structpb.NewValue, the value can also be
+// a []string or a *structpb.Value.
+func (task *Task) SetOutput(key string, value interface{}) error {
+	v, err := outputValue(value)
+	if err != nil {
+		return fmt.Errorf("invalid value of output '%s': %w", key, err)
+	}
+
+	task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+		if p.Outputs == nil {
+			p.Outputs = map[string]*structpb.Value{}
+		}
+		p.Outputs[key] = v
+		return p
+	})
+
+	return nil
+}
+
+// Output returns the output of the task set under `key`.
+func (task *Task) Output(key string) (*structpb.Value, error) {
+	return taskOutput(task, key)
+}
+
+// OutputOf returns the output `key` of the task at `path`, resolved relative to this task by Lookup. For example,
+// the output of a sibling task can be read using `[]string{"..", "canary"}`.
+func (task *Task) OutputOf(path []string, key string) (*structpb.Value, error) {
+	t := task.Lookup(path)
+	if t == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, strings.Join(path, "/"))
+	}
+
+	return taskOutput(t, key)
+}
+
+// Lookup returns the task at `path` relative to this task, or nil if there's no such task. Besides the children's
+// names, the path can contain ParentPath segments referring to the parent task.
+func (task *Task) Lookup(path []string) TaskInterface {
+	var t TaskInterface = task
+	for _, name := range path {
+		if name == ParentPath {
+			s, ok := t.(interface{ getParent() *Task })
+			if !ok {
+				return nil
+			}
+			parent := s.getParent()
+			if parent == nil {
+				return nil
+			}
+			t = parent
+			continue
+		}
+
+		t = getChild(t, name)
+		if t == nil {
+			return nil
+		}
+	}
+
+	return t
+}
+
+// taskOutput returns the output `key` of any task.
+func taskOutput(task TaskInterface, key string) (*structpb.Value, error) {
+	v, ok := taskProto(task).Outputs[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s' of task '%s'", ErrOutputNotFound, key, task.Name())
+	}
+
+	return proto.Clone(v).(*structpb.Value), nil
+}
+
+// outputValue converts a Go value to an output value.
+func outputValue(value interface{}) (*structpb.Value, error) {
+	switch v := value.(type) {
+	case *structpb.Value:
+		return proto.Clone(v).(*structpb.Value), nil
+
+	case []string:
+		l := make([]interface{}, len(v))
+		for i, s := range v {
+			l[i] = s
+		}
+		return structpb.NewValue(l)
+	}
+
+	return structpb.NewValue(value)
+}
@@ -0,0 +1,168 @@
+package rnr
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestTask_Output(t *testing.T) {
+	task := NewTask("task", false, nil)
+
+	if err := task.SetOutput("build", "1234"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := task.SetOutput("hosts", []string{"a", "b"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := task.SetOutput("invalid", struct{}{}); err == nil {
+		t.Errorf("expecting an error when setting an unsupported value")
+	}
+
+	if v, err := task.Output("build"); err != nil || v.GetStringValue() != "1234" {
+		t.Errorf("expecting build to be 1234, got %v (%v)", v, err)
+	}
+	v, err := task.Output("hosts")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hosts := v.GetListValue().AsSlice(); len(hosts) != 2 || hosts[0] != "a" || hosts[1] != "b" {
+		t.Errorf("expecting hosts to be [a b], got %v", hosts)
+	}
+	if _, err := task.Output("invalid"); !errors.Is(err, ErrOutputNotFound) {
+		t.Errorf("expecting ErrOutputNotFound, got %v", err)
+	}
+
+	// The outputs are published in the protobuf.
+	if got := task.Proto(nil).Outputs["build"].GetStringValue(); got != "1234" {
+		t.Errorf("expecting build to be published in the proto, got %q", got)
+	}
+}
+
+func TestTask_OutputOf(t *testing.T) {
+	root := NewNestedTask("root", NestedTaskOptions{})
+	deploy := NewNestedTask("deploy", NestedTaskOptions{})
+	canary := NewCallbackTask("canary", func(ctx context.Context, p *pb.Task) *pb.Task { return p })
+	rollout := NewCallbackTask("rollout", func(ctx context.Context, p *pb.Task) *pb.Task { return p })
+	root.Add(deploy)
+	deploy.Add(canary)
+	deploy.Add(rollout)
+
+	root.SetOutput("env", "prod")
+	canary.SetOutput("build", 42)
+
+	if v, err := rollout.OutputOf([]string{"..", "canary"}, "build"); err != nil || v.GetNumberValue() != 42 {
+		t.Errorf("expecting the sibling's build to be 42, got %v (%v)", v, err)
+	}
+	if v, err := rollout.OutputOf([]string{"..", ".."}, "env"); err != nil || v.GetStringValue() != "prod" {
+		t.Errorf("expecting the ancestor's env to be prod, got %v (%v)", v, err)
+	}
+	if _, err := rollout.OutputOf([]string{"..", "nope"}, "build"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("expecting ErrTaskNotFound, got %v", err)
+	}
+	if _, err := root.OutputOf([]string{".."}, "env"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("expecting ErrTaskNotFound above the root, got %v", err)
+	}
+
+	j := NewJob(root)
+	if v, err := j.Output([]string{"deploy", "canary"}, "build"); err != nil || v.GetNumberValue() != 42 {
+		t.Errorf("expecting the job to return build 42, got %v (%v)", v, err)
+	}
+}
+
+func TestCallbackTask_Outputs(t *testing.T) {
+	root := NewNestedTask("root", NestedTaskOptions{})
+	var consumer *CallbackTask
+	producer := NewCallbackTask("producer", func(ctx context.Context, p *pb.Task) *pb.Task {
+		p.Outputs = map[string]*structpb.Value{"build": structpb.NewStringValue("1234")}
+		p.State = pb.TaskState_SUCCESS
+		return p
+	})
+	var got string
+	consumer = NewCallbackTask("consumer", func(ctx context.Context, p *pb.Task) *pb.Task {
+		v, err := consumer.OutputOf([]string{"..", "producer"}, "build")
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return p
+		}
+		got = v.GetStringValue()
+		p.State = pb.TaskState_SUCCESS
+		return p
+	})
+	root.Add(producer)
+	root.Add(consumer)
+	root.SetState(pb.TaskState_RUNNING)
+
+	for i := 0; i < 5; i++ {
+		root.Poll(context.Background())
+	}
+
+	if root.Proto(nil).State != pb.TaskState_SUCCESS {
+		t.Fatalf("expecting the root to succeed, got %v", root.Proto(nil))
+	}
+	if got != "1234" {
+		t.Errorf("expecting the consumer to read build 1234, got %q", got)
+	}
+}
+
+func TestCallbackTask_OutputOfConcurrent(t *testing.T) {
+	root := NewNestedTask("root", NestedTaskOptions{Parallelism: 2})
+	var consumer *CallbackTask
+	producer := NewCallbackTask("producer", func(ctx context.Context, p *pb.Task) *pb.Task {
+		p.Outputs = map[string]*structpb.Value{"build": structpb.NewStringValue("1234")}
+		p.State = pb.TaskState_SUCCESS
+		return p
+	})
+	consumer = NewCallbackTask("consumer", func(ctx context.Context, p *pb.Task) *pb.Task {
+		// Reading the tree from the callback mustn't block the readers of the job, which get to run meanwhile.
+		time.Sleep(time.Millisecond)
+		if v, err := consumer.OutputOf([]string{"..", "producer"}, "build"); err == nil && v.GetStringValue() == "1234" {
+			p.State = pb.TaskState_SUCCESS
+		}
+		return p
+	})
+	root.Add(producer)
+	root.Add(consumer)
+	root.SetState(pb.TaskState_RUNNING)
+	j := NewJob(root)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					j.Proto(nil)
+				}
+			}
+		}()
+	}
+
+	polled := make(chan struct{})
+	go func() {
+		defer close(polled)
+		for i := 0; i < 100 && taskProto(root).State == pb.TaskState_RUNNING; i++ {
+			j.Poll(context.Background())
+		}
+	}()
+
+	select {
+	case <-polled:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("polling the job got stuck")
+	}
+	close(stop)
+	wg.Wait()
+
+	compareTaskStates(t, []TaskInterface{root, producer, consumer}, []pb.TaskState{pb.TaskState_SUCCESS, pb.TaskState_SUCCESS, pb.TaskState_SUCCESS})
+}
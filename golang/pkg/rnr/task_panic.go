@@ -0,0 +1,28 @@
+package rnr
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+// recoverPanic turns a panic in the task's code into a FAILED state, keeping the stack trace in the proto.
+// It has to be called directly by a deferred function.
+func (task *Task) recoverPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	stack := string(debug.Stack())
+	log.Printf("Recovered from panic in task '%s': %v\n%s", task.Name(), r, stack)
+
+	task.Proto(func(p *pb.Task) *pb.Task {
+		p.State = pb.TaskState_FAILED
+		p.Message = fmt.Sprintf("panic: %v", r)
+		p.StackTrace = stack
+		return p
+	})
+}
@@ -0,0 +1,81 @@
+package rnr
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+func comparePanic(t *testing.T, task TaskInterface, value string) {
+	t.Helper()
+
+	p := task.Proto(nil)
+	if p.State != pb.TaskState_FAILED {
+		t.Errorf("expecting panicking task to be FAILED, got %v", p.State)
+	}
+	if exp := "panic: " + value; p.Message != exp {
+		t.Errorf("expecting message to be %q, got %q", exp, p.Message)
+	}
+	if !strings.Contains(p.StackTrace, "goroutine") {
+		t.Errorf("expecting stack trace to be kept, got %q", p.StackTrace)
+	}
+}
+
+func TestTask_PanicInCallback(t *testing.T) {
+	ctx := context.TODO()
+	nt := NewNestedTask("nested", NestedTaskOptions{Parallelism: 2, CompleteAll: true})
+	ct := NewCallbackTask("panicking", func(context.Context, *pb.Task) *pb.Task {
+		panic("boom")
+	})
+	mt := newMockTask("mock", pb.TaskState_SUCCESS, nil)
+	nt.Add(ct)
+	nt.Add(mt)
+	nt.SetState(pb.TaskState_RUNNING)
+
+	nt.Poll(ctx)
+
+	comparePanic(t, ct, "boom")
+	compareTaskStates(t, []TaskInterface{mt, nt}, []pb.TaskState{pb.TaskState_SUCCESS, pb.TaskState_FAILED})
+}
+
+func TestTask_PanicInCustomPoll(t *testing.T) {
+	nt := NewNestedTask("nested", NestedTaskOptions{
+		CustomPoll: func(*Task, []TaskInterface) { panic("custom poll") },
+	})
+	nt.SetState(pb.TaskState_RUNNING)
+
+	nt.Poll(context.TODO())
+
+	comparePanic(t, nt, "custom poll")
+}
+
+func TestTask_PanicInAsyncTask(t *testing.T) {
+	at := NewAsyncTask("async", context.Background(), false, func(context.Context, func(StateUpdateCallback) *pb.Task) {
+		panic("async")
+	})
+	at.SetState(pb.TaskState_RUNNING)
+
+	at.Poll(context.TODO())
+	time.Sleep(tick)
+
+	comparePanic(t, at, "async")
+}
+
+type panickingTask struct {
+	*Task
+}
+
+func (pt *panickingTask) Poll(context.Context) {
+	panic("custom root")
+}
+
+func TestJob_PanicInRoot(t *testing.T) {
+	j := NewJob(&panickingTask{NewTask("root", false, nil)})
+
+	// Shouldn't propagate the panic
+	j.Poll(context.TODO())
+	j.Poll(context.TODO())
+}
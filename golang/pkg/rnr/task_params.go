@@ -0,0 +1,248 @@
+package rnr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+	proto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var (
+	ErrParamNotFound     = errors.New("parameter not found")
+	ErrInvalidParam      = errors.New("invalid parameter value")
+	ErrParamsNotEditable = errors.New("parameters can only be changed while the task is PENDING or ACTION_NEEDED")
+)
+
+// ParamSpec declares an input parameter of a task.
+type ParamSpec struct {
+	Name        string
+	Type        pb.ParamType
+	Description string
+	Default     interface{}                 // optional; converted to the parameter's type
+	Validate    func(*structpb.Value) error // optional; called with values of the parameter's type
+}
+
+// paramsEditable returns whether the parameters of a task in `state` can be changed.
+func paramsEditable(state pb.TaskState) bool {
+	return state == pb.TaskState_PENDING || state == pb.TaskState_ACTION_NEEDED
+}
+
+// AddParam declares an input parameter of the task; it should be called before the task is used.
+func (task *Task) AddParam(spec ParamSpec) error {
+	param := &pb.Param{
+		Name:        spec.Name,
+		Type:        spec.Type,
+		Description: spec.Description,
+	}
+	if spec.Default != nil {
+		v, err := outputValue(spec.Default)
+		if err == nil {
+			v, err = spec.paramValue(v)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid default of parameter '%s': %w", spec.Name, err)
+		}
+		param.DefaultValue = v
+		param.Value = v
+	}
+
+	task.mu.Lock()
+	_, exists := task.params[spec.Name]
+	if !exists {
+		if task.params == nil {
+			task.params = map[string]ParamSpec{}
+		}
+		task.params[spec.Name] = spec
+	}
+	task.mu.Unlock()
+	if exists {
+		return fmt.Errorf("parameter '%s' already exists", spec.Name)
+	}
+
+	task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+		p.Params = append(p.Params, param)
+		return p
+	})
+
+	return nil
+}
+
+// Param returns the current value of the parameter `name`; unset parameters are null.
+func (task *Task) Param(name string) (*structpb.Value, error) {
+	return ParamValue(task.snapshot(), name)
+}
+
+// ParamValue returns the current value of the parameter `name` of a task's protobuf; unset parameters are null.
+func ParamValue(p *pb.Task, name string) (*structpb.Value, error) {
+	for _, param := range p.Params {
+		if param.Name != name {
+			continue
+		}
+		if param.Value == nil {
+			return structpb.NewNullValue(), nil
+		}
+		return proto.Clone(param.Value).(*structpb.Value), nil
+	}
+
+	return nil, fmt.Errorf("%w: '%s' of task '%s'", ErrParamNotFound, name, p.Name)
+}
+
+// SetParams changes the values of the task's parameters. The values are converted to the parameters' types and
+// validated first; either all of them are set or none. Parameters can only be changed while the task is PENDING or
+// ACTION_NEEDED, so that they don't change under a running task.
+func (task *Task) SetParams(values map[string]*structpb.Value) error {
+	return task.updateParams(pb.TransitionSource_SOURCE_TASK, values, nil)
+}
+
+// updateParams sets the parameters like SetParams, letting `then` make further changes to the protobuf in the same
+// update. Nothing is changed if either of them fails.
+func (task *Task) updateParams(source pb.TransitionSource, values map[string]*structpb.Value, then func(*pb.Task) error) error {
+	// The values are converted up front, as the updater may be retried and the validators are user code.
+	converted, convErr := task.convertParams(values)
+
+	var err error
+	task.updateFrom(source, func(p *pb.Task) *pb.Task {
+		err = nil
+		if !paramsEditable(p.State) {
+			err = fmt.Errorf("%w: task '%s' is %s", ErrParamsNotEditable, p.Name, p.State)
+			return p
+		}
+		if convErr != nil {
+			err = convErr
+			return p
+		}
+
+		if then != nil {
+			if err = then(p); err != nil {
+				return p
+			}
+		}
+
+		for _, param := range p.Params {
+			if v, ok := converted[param.Name]; ok {
+				param.Value = v
+			}
+		}
+		return p
+	})
+
+	return err
+}
+
+// convertParams converts and validates `values` according to the task's parameter declarations.
+func (task *Task) convertParams(values map[string]*structpb.Value) (map[string]*structpb.Value, error) {
+	converted := make(map[string]*structpb.Value, len(values))
+	for name, v := range values {
+		spec, ok := task.paramSpec(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: '%s' of task '%s'", ErrParamNotFound, name, task.Name())
+		}
+		value, err := spec.paramValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("parameter '%s' of task '%s': %w", name, task.Name(), err)
+		}
+		converted[name] = value
+	}
+
+	return converted, nil
+}
+
+// paramSpec returns the declaration of the parameter `name`.
+func (task *Task) paramSpec(name string) (ParamSpec, bool) {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+
+	spec, ok := task.params[name]
+	return spec, ok
+}
+
+// paramsSetter sets the parameters declared by a task.
+type paramsSetter interface {
+	updateParams(pb.TransitionSource, map[string]*structpb.Value, func(*pb.Task) error) error
+}
+
+// setParams sets the parameters of any task; `then` can make further changes to the protobuf in the same update,
+// see updateParams.
+func setParams(task TaskInterface, source pb.TransitionSource, values map[string]*structpb.Value, then func(*pb.Task) error) error {
+	t, ok := task.(paramsSetter)
+	if !ok {
+		return fmt.Errorf("%w: task '%s' has no parameters", ErrParamNotFound, task.Name())
+	}
+
+	return t.updateParams(source, values, then)
+}
+
+// paramValue converts `v` to the parameter's type and validates it.
+func (spec ParamSpec) paramValue(v *structpb.Value) (*structpb.Value, error) {
+	ret, err := convertParam(spec.Type, v)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Validate != nil {
+		if err := spec.Validate(ret); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidParam, err.Error())
+		}
+	}
+
+	return ret, nil
+}
+
+// convertParam converts `v` to a value of type `t`. Strings are parsed, so that the values can be entered as text.
+func convertParam(t pb.ParamType, v *structpb.Value) (*structpb.Value, error) {
+	if v == nil {
+		return nil, fmt.Errorf("%w: missing value", ErrInvalidParam)
+	}
+
+	switch k := v.GetKind().(type) {
+	case *structpb.Value_StringValue:
+		switch t {
+		case pb.ParamType_PARAM_STRING:
+			return structpb.NewStringValue(k.StringValue), nil
+
+		case pb.ParamType_PARAM_INT:
+			i, err := strconv.ParseInt(k.StringValue, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidParam, err.Error())
+			}
+			return structpb.NewNumberValue(float64(i)), nil
+
+		case pb.ParamType_PARAM_FLOAT:
+			f, err := strconv.ParseFloat(k.StringValue, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidParam, err.Error())
+			}
+			return structpb.NewNumberValue(f), nil
+
+		case pb.ParamType_PARAM_BOOL:
+			b, err := strconv.ParseBool(k.StringValue)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidParam, err.Error())
+			}
+			return structpb.NewBoolValue(b), nil
+		}
+
+	case *structpb.Value_NumberValue:
+		switch t {
+		case pb.ParamType_PARAM_INT:
+			if k.NumberValue != math.Trunc(k.NumberValue) {
+				return nil, fmt.Errorf("%w: %v is not an integer", ErrInvalidParam, k.NumberValue)
+			}
+			return structpb.NewNumberValue(k.NumberValue), nil
+
+		case pb.ParamType_PARAM_FLOAT:
+			return structpb.NewNumberValue(k.NumberValue), nil
+		}
+
+	case *structpb.Value_BoolValue:
+		if t == pb.ParamType_PARAM_BOOL {
+			return structpb.NewBoolValue(k.BoolValue), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: can't convert %v to %s", ErrInvalidParam, v.AsInterface(), t)
+}
@@ -0,0 +1,171 @@
+package rnr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestTask_Params(t *testing.T) {
+	task := NewTask("task", false, nil)
+
+	for _, spec := range []ParamSpec{
+		{Name: "version", Type: pb.ParamType_PARAM_STRING},
+		{Name: "batch", Type: pb.ParamType_PARAM_INT, Default: "10", Validate: func(v *structpb.Value) error {
+			if v.GetNumberValue() < 1 {
+				return fmt.Errorf("must be positive")
+			}
+			return nil
+		}},
+		{Name: "ratio", Type: pb.ParamType_PARAM_FLOAT, Default: 0.5},
+		{Name: "dry_run", Type: pb.ParamType_PARAM_BOOL, Default: true},
+	} {
+		if err := task.AddParam(spec); err != nil {
+			t.Fatalf("unexpected error when adding %s: %v", spec.Name, err)
+		}
+	}
+
+	if err := task.AddParam(ParamSpec{Name: "batch", Type: pb.ParamType_PARAM_INT}); err == nil {
+		t.Errorf("expecting an error when adding a duplicate parameter")
+	}
+	if err := task.AddParam(ParamSpec{Name: "bad", Type: pb.ParamType_PARAM_INT, Default: "x"}); !errors.Is(err, ErrInvalidParam) {
+		t.Errorf("expecting ErrInvalidParam for an invalid default, got %v", err)
+	}
+
+	check := func(name string, want interface{}) {
+		t.Helper()
+		v, err := task.Param(name)
+		if err != nil {
+			t.Fatalf("unexpected error when reading %s: %v", name, err)
+		}
+		if got := v.AsInterface(); got != want {
+			t.Errorf("expecting %s to be %v, got %v", name, want, got)
+		}
+	}
+
+	check("version", nil)
+	check("batch", float64(10))
+	check("ratio", 0.5)
+	check("dry_run", true)
+
+	if _, err := task.Param("nope"); !errors.Is(err, ErrParamNotFound) {
+		t.Errorf("expecting ErrParamNotFound, got %v", err)
+	}
+
+	// Values entered as text are converted to the parameters' types.
+	err := task.SetParams(map[string]*structpb.Value{
+		"version": structpb.NewStringValue("1.2.3"),
+		"batch":   structpb.NewStringValue("20"),
+		"dry_run": structpb.NewStringValue("false"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	check("version", "1.2.3")
+	check("batch", float64(20))
+	check("dry_run", false)
+
+	// Either all the parameters are set, or none.
+	for _, values := range []map[string]*structpb.Value{
+		{"version": structpb.NewStringValue("2.0"), "batch": structpb.NewStringValue("0")},
+		{"version": structpb.NewStringValue("2.0"), "batch": structpb.NewNumberValue(1.5)},
+		{"version": structpb.NewStringValue("2.0"), "ratio": structpb.NewBoolValue(true)},
+	} {
+		if err := task.SetParams(values); !errors.Is(err, ErrInvalidParam) {
+			t.Errorf("expecting ErrInvalidParam for %v, got %v", values, err)
+		}
+	}
+	if err := task.SetParams(map[string]*structpb.Value{"nope": structpb.NewStringValue("x")}); !errors.Is(err, ErrParamNotFound) {
+		t.Errorf("expecting ErrParamNotFound, got %v", err)
+	}
+	check("version", "1.2.3")
+	check("batch", float64(20))
+
+	// The parameters can't change under a running task.
+	task.SetState(pb.TaskState_RUNNING)
+	if err := task.SetParams(map[string]*structpb.Value{"batch": structpb.NewStringValue("5")}); !errors.Is(err, ErrParamsNotEditable) {
+		t.Errorf("expecting ErrParamsNotEditable, got %v", err)
+	}
+	task.SetState(pb.TaskState_ACTION_NEEDED)
+	if err := task.SetParams(map[string]*structpb.Value{"batch": structpb.NewStringValue("5")}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	check("batch", float64(5))
+}
+
+func TestJob_TaskRequestParams(t *testing.T) {
+	root := NewNestedTask("root", NestedTaskOptions{})
+	var batch float64
+	ct := NewCallbackTask("stage", func(ctx context.Context, p *pb.Task) *pb.Task {
+		v, err := ParamValue(p, "batch")
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		batch = v.GetNumberValue()
+		p.State = pb.TaskState_SUCCESS
+		return p
+	})
+	ct.AddParam(ParamSpec{Name: "batch", Type: pb.ParamType_PARAM_INT, Default: 10})
+	root.Add(ct)
+	j := NewJob(root)
+	ws := NewRnrWebserver(j)
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"path": ["stage"], "params": {"batch": "x"}}`, http.StatusBadRequest},
+		{`{"path": ["stage"], "params": {"nope": "1"}}`, http.StatusBadRequest},
+		{`{"path": ["stage"], "params": {"batch": "x"}, "state": "RUNNING"}`, http.StatusBadRequest},
+		{`{"path": ["stage"], "params": {"batch": "25"}, "state": "RUNNING"}`, http.StatusOK},
+		{`{"path": ["stage"], "params": {"batch": "30"}}`, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		ws.tasksHandler(rec, httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(tt.body)))
+		if rec.Code != tt.code {
+			t.Errorf("expecting status %d for %s, got %d", tt.code, tt.body, rec.Code)
+		}
+	}
+
+	root.SetState(pb.TaskState_RUNNING)
+	j.Poll(context.Background())
+
+	if batch != 25 {
+		t.Errorf("expecting the task to run with batch 25, got %v", batch)
+	}
+}
+
+func TestJob_TaskRequestParamsWithIllegalTransition(t *testing.T) {
+	root := NewNestedTask("root", NestedTaskOptions{})
+	ct := NewCallbackTask("stage", func(ctx context.Context, p *pb.Task) *pb.Task { return p })
+	ct.AddParam(ParamSpec{Name: "batch", Type: pb.ParamType_PARAM_INT, Default: 10})
+	ct.SetTransitions(Transitions{pb.TaskState_PENDING: {pb.TaskState_RUNNING}})
+	root.Add(ct)
+	j := NewJob(root)
+
+	err := j.TaskRequest(&pb.TaskRequest{
+		Path:   []string{"stage"},
+		Params: map[string]*structpb.Value{"batch": structpb.NewStringValue("25")},
+		State:  pb.TaskState_SKIPPED,
+	})
+	var ite *IllegalTransitionError
+	if !errors.As(err, &ite) {
+		t.Fatalf("expecting an IllegalTransitionError, got %v", err)
+	}
+
+	if v, _ := ct.Param("batch"); v.GetNumberValue() != 10 {
+		t.Errorf("expecting a rejected request not to change the parameters, got %v", v.AsInterface())
+	}
+	if state := ct.Proto(nil).State; state != pb.TaskState_PENDING {
+		t.Errorf("expecting the task to stay PENDING, got %v", state)
+	}
+}
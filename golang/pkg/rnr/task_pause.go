@@ -0,0 +1,111 @@
+package rnr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+var (
+	ErrNotPausable = errors.New("task can't be paused")
+	ErrNotPaused   = errors.New("task is not paused")
+)
+
+// Suspender is implemented by tasks whose long-running work can be suspended while they're paused, such as ShellTask.
+type Suspender interface {
+	// Suspend suspends the task's work.
+	Suspend() error
+	// Resume resumes the task's work suspended by Suspend; it does nothing if the work isn't suspended.
+	Resume() error
+}
+
+// Pause pauses `task` along with its subtree. Paused tasks keep the state they were in (`paused_from`), so that Resume
+// can put it back; they don't schedule any new work in the meantime, but keep their slots and pool units.
+//
+// The tasks that have already started their own work, i.e. the running leaves, keep running, unless `suspend` is set.
+// They aren't polled while their parent is paused, though, so their results are only picked up after resuming. With
+// `suspend`, the running leaves are paused too: the ones implementing Suspender get suspended, the others have their
+// context cancelled, like any paused task, and start their work again once resumed (e.g. AsyncTask restarts its
+// function). Pausing bypasses the tasks' transitions.
+func Pause(task TaskInterface, suspend bool) error {
+	switch tpb := taskProto(task); taskSchedState(tpb) {
+	case PENDING, RUNNING:
+	default:
+		return fmt.Errorf("%w: task '%s' is %s", ErrNotPausable, tpb.Name, tpb.State)
+	}
+
+	return Walk(task, func(path []string, t TaskInterface) error {
+		tpb := taskProto(t)
+		switch taskSchedState(tpb) {
+		case DONE:
+			return ErrSkipChildren
+		case PENDING, RUNNING:
+		default:
+			// Already paused by an earlier request; keep the state saved back then.
+			return nil
+		}
+
+		if len(path) > 0 && len(t.Children()) == 0 {
+			if taskSchedState(tpb) != RUNNING {
+				// Pending leaves won't start while their parent is paused.
+				return nil
+			}
+			if !suspend {
+				return nil
+			}
+			if s, ok := t.(Suspender); ok {
+				if err := s.Suspend(); err != nil {
+					return fmt.Errorf("suspending '%s': %w", tpb.Name, err)
+				}
+			}
+		}
+
+		updateProto(t, pb.TransitionSource_SOURCE_REQUEST, func(p *pb.Task) *pb.Task {
+			// The state might have changed in the meantime
+			if s := taskSchedState(p); s == PENDING || s == RUNNING {
+				p.PausedFrom = p.State
+				p.State = pb.TaskState_PAUSED
+			}
+			return p
+		})
+		return nil
+	})
+}
+
+// Resume puts back the states of `task` and its subtree from before they were paused, resuming any suspended work.
+func Resume(task TaskInterface) error {
+	if tpb := taskProto(task); tpb.State != pb.TaskState_PAUSED {
+		return fmt.Errorf("%w: task '%s' is %s", ErrNotPaused, tpb.Name, tpb.State)
+	}
+
+	return Walk(task, func(path []string, t TaskInterface) error {
+		tpb := taskProto(t)
+		if taskSchedState(tpb) == DONE {
+			return ErrSkipChildren
+		}
+		if tpb.State != pb.TaskState_PAUSED {
+			return nil
+		}
+
+		if s, ok := t.(Suspender); ok {
+			if err := s.Resume(); err != nil {
+				return fmt.Errorf("resuming '%s': %w", tpb.Name, err)
+			}
+		}
+
+		updateProto(t, pb.TransitionSource_SOURCE_REQUEST, func(p *pb.Task) *pb.Task {
+			if p.State != pb.TaskState_PAUSED {
+				return p
+			}
+			p.State = p.PausedFrom
+			if p.State == pb.TaskState_UNKNOWN {
+				// Paused by forcing the state rather than by Pause.
+				p.State = pb.TaskState_PENDING
+			}
+			p.PausedFrom = pb.TaskState_UNKNOWN
+			return p
+		})
+		return nil
+	})
+}
@@ -0,0 +1,163 @@
+package rnr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+func TestPause(t *testing.T) {
+	ctx := context.Background()
+	root := NewNestedTask("root", NestedTaskOptions{Parallelism: 2})
+	a := newMockTask("a", pb.TaskState_RUNNING, nil)
+	b := NewNestedTask("b", NestedTaskOptions{})
+	b1 := newMockTask("b1", pb.TaskState_RUNNING, nil)
+	c := newMockTask("c", pb.TaskState_SUCCESS, nil)
+	b.Add(b1)
+	root.Add(a)
+	root.Add(b)
+	root.Add(c)
+	tasks := []TaskInterface{root, a, b, b1, c}
+
+	root.SetState(pb.TaskState_RUNNING)
+	root.Poll(ctx)
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_RUNNING, pb.TaskState_RUNNING, pb.TaskState_RUNNING, pb.TaskState_RUNNING, pb.TaskState_PENDING})
+	started := root.Proto(nil).Started.AsTime()
+
+	// The schedulers get paused, the running leaves keep running.
+	if err := Pause(root, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a.SetState(pb.TaskState_SUCCESS)
+	root.Poll(ctx)
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_PAUSED, pb.TaskState_SUCCESS, pb.TaskState_PAUSED, pb.TaskState_RUNNING, pb.TaskState_PENDING})
+	if got := b.Proto(nil).PausedFrom; got != pb.TaskState_RUNNING {
+		t.Errorf("expecting paused_from RUNNING, got %v", got)
+	}
+	if err := Pause(root, false); !errors.Is(err, ErrNotPausable) {
+		t.Errorf("expecting ErrNotPausable, got %v", err)
+	}
+
+	// Resuming puts the states back without restarting the tasks.
+	if err := Resume(root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_RUNNING, pb.TaskState_SUCCESS, pb.TaskState_RUNNING, pb.TaskState_RUNNING, pb.TaskState_PENDING})
+	if p := root.Proto(nil); !p.Started.AsTime().Equal(started) || p.PausedFrom != pb.TaskState_UNKNOWN {
+		t.Errorf("unexpected protobuf after resuming: %v", p)
+	}
+	if err := Resume(root); !errors.Is(err, ErrNotPaused) {
+		t.Errorf("expecting ErrNotPaused, got %v", err)
+	}
+
+	root.Poll(ctx)
+	compareTaskStates(t, tasks[4:], []pb.TaskState{pb.TaskState_SUCCESS})
+}
+
+func TestPause_SuspendShellTask(t *testing.T) {
+	ctx := context.Background()
+	root := NewNestedTask("root", NestedTaskOptions{})
+	st := NewShellTask("shell", "sh", "-c", "sleep 0.2; echo done")
+	root.Add(st)
+	root.SetState(pb.TaskState_RUNNING)
+	root.Poll(ctx)
+
+	if err := Pause(root, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	compareTaskStates(t, []TaskInterface{root, st}, []pb.TaskState{pb.TaskState_PAUSED, pb.TaskState_PAUSED})
+
+	// The command doesn't get any further while suspended.
+	time.Sleep(500 * time.Millisecond)
+	st.Poll(ctx)
+	if got := logTexts(st.Logs(0)); len(got) != 0 {
+		t.Errorf("expecting no output while suspended, got %v", got)
+	}
+
+	if err := Resume(root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 500 && st.Proto(nil).State == pb.TaskState_RUNNING; i++ {
+		root.Poll(ctx)
+		time.Sleep(tick)
+	}
+	compareTaskStates(t, []TaskInterface{st}, []pb.TaskState{pb.TaskState_SUCCESS})
+	if got := logTexts(st.Logs(0)); len(got) != 1 || got[0] != "done" {
+		t.Errorf("unexpected output %v", got)
+	}
+}
+
+func TestPause_CancelAsyncTask(t *testing.T) {
+	ctx := context.Background()
+	root := NewNestedTask("root", NestedTaskOptions{})
+	started := make(chan struct{}, 2)
+	stopped := make(chan struct{}, 2)
+	at := NewAsyncTask("async", ctx, false, func(ctx context.Context, update func(StateUpdateCallback) *pb.Task) {
+		started <- struct{}{}
+		<-ctx.Done()
+		stopped <- struct{}{}
+	})
+	root.Add(at)
+	root.SetState(pb.TaskState_RUNNING)
+	root.Poll(ctx)
+	defer root.Cancel()
+
+	wait := func(ch chan struct{}, what string) {
+		select {
+		case <-ch:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expecting the function to be %s", what)
+		}
+	}
+	wait(started, "started")
+
+	// AsyncTask can't be suspended, so it's cancelled instead.
+	if err := Pause(root, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	compareTaskStates(t, []TaskInterface{root, at}, []pb.TaskState{pb.TaskState_PAUSED, pb.TaskState_PAUSED})
+	wait(stopped, "cancelled")
+
+	// Resuming starts it again.
+	if err := Resume(root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{root, at}, []pb.TaskState{pb.TaskState_RUNNING, pb.TaskState_RUNNING})
+	wait(started, "restarted")
+}
+
+func TestRnrWebServer_Pause(t *testing.T) {
+	root := NewNestedTask("root", NestedTaskOptions{})
+	root.Add(newMockTask("done", pb.TaskState_SUCCESS, nil))
+	root.Add(NewNestedTask("branch", NestedTaskOptions{}))
+	root.GetChild("done").Proto(func(p *pb.Task) *pb.Task {
+		p.State = pb.TaskState_SUCCESS
+		return p
+	})
+	ws := NewRnrWebserver(NewJob(root))
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"path": ["nope"]}`, http.StatusNotFound},
+		{`{"path": ["done"]}`, http.StatusConflict},
+		{`{"path": ["branch"], "resume": true}`, http.StatusConflict},
+		{`{"path": ["branch"]}`, http.StatusOK},
+		{`{"path": ["branch"], "resume": true}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		ws.pauseHandler(rec, httptest.NewRequest(http.MethodPost, "/pause", strings.NewReader(tt.body)))
+		if rec.Code != tt.code {
+			t.Errorf("expecting status %d for %s, got %d", tt.code, tt.body, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package rnr
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+// TaskOrder compares two tasks waiting to be started by a scheduler: it returns a negative number if `a` should start
+// before `b`, a positive one if after, and 0 if the order doesn't matter.
+type TaskOrder func(a, b *pb.Task) int
+
+// ByPriority starts the tasks with a higher priority first.
+func ByPriority(a, b *pb.Task) int {
+	switch {
+	case a.Priority > b.Priority:
+		return -1
+	case a.Priority < b.Priority:
+		return 1
+	}
+	return 0
+}
+
+// ByExpectedDuration starts the tasks expected to take the shortest time first.
+func ByExpectedDuration(expected func(*pb.Task) time.Duration) TaskOrder {
+	return func(a, b *pb.Task) int {
+		da, db := expected(a), expected(b)
+		switch {
+		case da < db:
+			return -1
+		case da > db:
+			return 1
+		}
+		return 0
+	}
+}
+
+// Shuffled orders the tasks randomly; the order is determined by the seed, so it doesn't change between polls.
+func Shuffled(seed int64) TaskOrder {
+	key := func(p *pb.Task) uint64 {
+		h := fnv.New64a()
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], uint64(seed))
+		h.Write(b[:])
+		h.Write([]byte(p.Name))
+		return h.Sum64()
+	}
+
+	return func(a, b *pb.Task) int {
+		ka, kb := key(a), key(b)
+		switch {
+		case ka < kb:
+			return -1
+		case ka > kb:
+			return 1
+		}
+		return 0
+	}
+}
+
+// OrderBy combines the orders: the tasks are compared by the first order, ties are broken by the second one etc.
+// For example, OrderBy(ByPriority, ByExpectedDuration(f), Shuffled(seed)).
+func OrderBy(orders ...TaskOrder) TaskOrder {
+	return func(a, b *pb.Task) int {
+		for _, order := range orders {
+			if c := order(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
+
+// orderTasks returns the tasks sorted by `order`; the tasks the order doesn't distinguish keep their relative order.
+func orderTasks(tasks []TaskInterface, order TaskOrder) []TaskInterface {
+	if order == nil {
+		return tasks
+	}
+
+	type entry struct {
+		task  TaskInterface
+		proto *pb.Task
+	}
+	entries := make([]entry, len(tasks))
+	for i, t := range tasks {
+		entries[i] = entry{t, taskProto(t)}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return order(entries[i].proto, entries[j].proto) < 0
+	})
+
+	ret := make([]TaskInterface, len(entries))
+	for i, e := range entries {
+		ret[i] = e.task
+	}
+
+	return ret
+}
+
+// SetPriority sets the task's priority, used by the schedulers ordering their children ByPriority.
+func (task *Task) SetPriority(priority int32) {
+	task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+		p.Priority = priority
+		return p
+	})
+}
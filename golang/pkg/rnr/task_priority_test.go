@@ -0,0 +1,95 @@
+package rnr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+func taskNames(tasks []TaskInterface) []string {
+	var ret []string
+	for _, t := range tasks {
+		ret = append(ret, t.Name())
+	}
+	return ret
+}
+
+func TestOrderBy(t *testing.T) {
+	expected := map[string]time.Duration{"a": time.Hour, "b": time.Minute, "c": time.Minute, "d": time.Second}
+	var tasks []TaskInterface
+	for _, name := range []string{"a", "b", "c", "d"} {
+		tasks = append(tasks, NewTask(name, false, nil))
+	}
+	tasks[0].(*Task).SetPriority(1)
+
+	order := OrderBy(ByPriority, ByExpectedDuration(func(p *pb.Task) time.Duration { return expected[p.Name] }))
+	if got := taskNames(orderTasks(tasks, order)); !reflect.DeepEqual(got, []string{"a", "d", "b", "c"}) {
+		t.Errorf("unexpected order %v", got)
+	}
+
+	// A shuffle is stable for the same seed.
+	first := taskNames(orderTasks(tasks, Shuffled(42)))
+	if second := taskNames(orderTasks(tasks, Shuffled(42))); !reflect.DeepEqual(first, second) {
+		t.Errorf("shuffles with the same seed differ: %v, %v", first, second)
+	}
+}
+
+func TestNestedTask_ByPriority(t *testing.T) {
+	ctx := context.TODO()
+	nt := NewNestedTask("nested task test", NestedTaskOptions{Parallelism: 1, Order: ByPriority})
+	var pollCount int32
+	for i := 0; i < 3; i++ {
+		nt.Add(newMockTask(fmt.Sprintf("child %d", i), pb.TaskState_RUNNING, &pollCount))
+	}
+	job := NewJob(nt)
+
+	// Bump the last child to the front.
+	if err := job.PriorityRequest(&pb.PriorityRequest{Path: []string{"child 2"}, Priority: 10}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nt.SetState(pb.TaskState_RUNNING)
+	nt.Poll(ctx)
+	compareTaskStates(t, nt.Children(), []pb.TaskState{pb.TaskState_PENDING, pb.TaskState_PENDING, pb.TaskState_RUNNING})
+
+	// Equal priorities keep the order the children were added in.
+	nt.Children()[2].Proto(func(p *pb.Task) *pb.Task {
+		p.State = pb.TaskState_SUCCESS
+		return p
+	})
+	nt.Poll(ctx)
+	compareTaskStates(t, nt.Children(), []pb.TaskState{pb.TaskState_RUNNING, pb.TaskState_PENDING, pb.TaskState_SUCCESS})
+}
+
+func TestRnrWebServer_Priority(t *testing.T) {
+	nt := NewNestedTask("root", NestedTaskOptions{})
+	ct := newMockTask("host", pb.TaskState_SUCCESS, nil)
+	nt.Add(ct)
+	ws := NewRnrWebserver(NewJob(nt))
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"path": ["nope"], "priority": 1}`, http.StatusNotFound},
+		{`{"path": ["host"], "priority": 5}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		ws.priorityHandler(rec, httptest.NewRequest(http.MethodPost, "/priority", strings.NewReader(tt.body)))
+		if rec.Code != tt.code {
+			t.Errorf("expecting status %d for %s, got %d", tt.code, tt.body, rec.Code)
+		}
+	}
+
+	if got := ct.Proto(nil).Priority; got != 5 {
+		t.Errorf("expecting priority 5, got %d", got)
+	}
+}
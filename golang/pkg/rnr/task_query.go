@@ -0,0 +1,152 @@
+package rnr
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+// Paths are relative to the task they're resolved against and don't include its own name, i.e. the path of the task
+// itself is empty. The same convention is used by `pb.TaskRequest`.
+
+// ErrSkipChildren can be returned by a WalkFunc to skip the children of the visited task.
+var ErrSkipChildren = errors.New("skip children")
+
+// WalkFunc is called for each task visited by Walk, along with the task's path.
+type WalkFunc func(path []string, task TaskInterface) error
+
+// TaskPredicate is used to filter tasks in queries.
+type TaskPredicate func(TaskInterface) bool
+
+// TaskPath is a task found by a query, along with its path.
+type TaskPath struct {
+	Path []string
+	Task TaskInterface
+}
+
+// Find returns the descendant of `task` at `path`, or nil if there's no such task.
+func Find(task TaskInterface, path []string) TaskInterface {
+	for _, name := range path {
+		task = getChild(task, name)
+		if task == nil {
+			return nil
+		}
+	}
+
+	return task
+}
+
+// Walk visits `root` and all its descendants in depth-first order, calling `fn` for each of them. Walking stops at the
+// first error returned by `fn`, except for ErrSkipChildren, which only skips the children of the visited task.
+func Walk(root TaskInterface, fn WalkFunc) error {
+	return walk(nil, root, fn)
+}
+
+func walk(p []string, task TaskInterface, fn WalkFunc) error {
+	if err := fn(p, task); err != nil {
+		if errors.Is(err, ErrSkipChildren) {
+			return nil
+		}
+		return err
+	}
+
+	for _, c := range task.Children() {
+		cp := make([]string, len(p), len(p)+1)
+		copy(cp, p)
+		if err := walk(append(cp, c.Name()), c, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Query returns the descendants of `root` whose path matches `pattern` and which satisfy all the predicates.
+//
+// The pattern consists of `/`-separated segments matched against the names using path.Match, i.e. `deploy/*/canary`;
+// a `**` segment matches any number of names.
+func Query(root TaskInterface, pattern string, predicates ...TaskPredicate) ([]TaskPath, error) {
+	segments := strings.Split(pattern, "/")
+	for _, s := range segments {
+		if _, err := path.Match(s, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+		}
+	}
+
+	var ret []TaskPath
+	Walk(root, func(p []string, task TaskInterface) error {
+		if !matchPath(segments, p) {
+			return nil
+		}
+		for _, pred := range predicates {
+			if !pred(task) {
+				return nil
+			}
+		}
+		ret = append(ret, TaskPath{Path: p, Task: task})
+		return nil
+	})
+
+	return ret, nil
+}
+
+// matchPath returns whether `p` matches the pattern `segments`.
+func matchPath(segments []string, p []string) bool {
+	if len(segments) == 0 {
+		return len(p) == 0
+	}
+
+	if segments[0] == "**" {
+		for i := 0; i <= len(p); i++ {
+			if matchPath(segments[1:], p[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(p) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(segments[0], p[0]); !ok {
+		return false
+	}
+
+	return matchPath(segments[1:], p[1:])
+}
+
+// InState returns a predicate matching tasks in any of the specified states.
+func InState(states ...pb.TaskState) TaskPredicate {
+	return func(task TaskInterface) bool {
+		state := taskProto(task).State
+		for _, s := range states {
+			if s == state {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// IsLeaf is a predicate matching tasks without any children.
+func IsLeaf(task TaskInterface) bool {
+	return len(task.Children()) == 0
+}
+
+// Find returns the task's descendant at `path`, or nil if there's no such task.
+func (task *Task) Find(path []string) TaskInterface {
+	return Find(task, path)
+}
+
+// Walk visits the task and all its descendants; see Walk.
+func (task *Task) Walk(fn WalkFunc) error {
+	return Walk(task, fn)
+}
+
+// Query returns the task's descendants matching `pattern` and all the predicates; see Query.
+func (task *Task) Query(pattern string, predicates ...TaskPredicate) ([]TaskPath, error) {
+	return Query(task, pattern, predicates...)
+}
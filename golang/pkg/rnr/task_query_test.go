@@ -0,0 +1,165 @@
+package rnr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+// newQueryTree returns a tree of tasks: root/{deploy/{eu,us}/{canary,rest},cleanup}
+func newQueryTree() *NestedTask {
+	root := NewNestedTask("root", NestedTaskOptions{})
+	deploy := NewNestedTask("deploy", NestedTaskOptions{})
+	root.Add(deploy)
+	for _, region := range []string{"eu", "us"} {
+		r := NewNestedTask(region, NestedTaskOptions{})
+		deploy.Add(r)
+		r.Add(newMockTask("canary", pb.TaskState_SUCCESS, nil))
+		r.Add(newMockTask("rest", pb.TaskState_SUCCESS, nil))
+	}
+	root.Add(newMockTask("cleanup", pb.TaskState_SUCCESS, nil))
+
+	return root
+}
+
+func queryPaths(matches []TaskPath) []string {
+	ret := []string{}
+	for _, m := range matches {
+		ret = append(ret, strings.Join(m.Path, "/"))
+	}
+	return ret
+}
+
+func TestTask_Find(t *testing.T) {
+	root := newQueryTree()
+
+	if task := root.Find([]string{"deploy", "us", "canary"}); task == nil || task.Name() != "canary" {
+		t.Errorf("expecting to find deploy/us/canary, got %v", task)
+	}
+	if task := root.Find(nil); task != root.Task {
+		t.Errorf("expecting empty path to return the task itself, got %v", task)
+	}
+	if task := root.Find([]string{"deploy", "asia", "canary"}); task != nil {
+		t.Errorf("expecting nil for a non-existent path, got %v", task)
+	}
+}
+
+func TestTask_Walk(t *testing.T) {
+	root := newQueryTree()
+
+	var visited []string
+	err := root.Walk(func(p []string, task TaskInterface) error {
+		visited = append(visited, strings.Join(p, "/"))
+		if task.Name() == "eu" {
+			return ErrSkipChildren
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := []string{"", "deploy", "deploy/eu", "deploy/us", "deploy/us/canary", "deploy/us/rest", "cleanup"}
+	if !reflect.DeepEqual(visited, exp) {
+		t.Errorf("expecting visited paths %v, got %v", exp, visited)
+	}
+
+	stop := errors.New("stop")
+	if err := root.Walk(func([]string, TaskInterface) error { return stop }); err != stop {
+		t.Errorf("expecting Walk to return the error, got %v", err)
+	}
+}
+
+func TestTask_Query(t *testing.T) {
+	root := newQueryTree()
+	root.Find([]string{"deploy", "eu", "canary"}).(*Task).SetState(pb.TaskState_FAILED)
+	root.Find([]string{"cleanup"}).(*Task).SetState(pb.TaskState_FAILED)
+
+	tests := []struct {
+		pattern    string
+		predicates []TaskPredicate
+		exp        []string
+	}{
+		{"deploy/*/canary", nil, []string{"deploy/eu/canary", "deploy/us/canary"}},
+		{"deploy/u?", nil, []string{"deploy/us"}},
+		{"**/rest", nil, []string{"deploy/eu/rest", "deploy/us/rest"}},
+		{"**", []TaskPredicate{InState(pb.TaskState_FAILED), IsLeaf}, []string{"deploy/eu/canary", "cleanup"}},
+		{"deploy/**", []TaskPredicate{InState(pb.TaskState_FAILED)}, []string{"deploy/eu/canary"}},
+		{"foo", nil, []string{}},
+	}
+
+	for _, tt := range tests {
+		matches, err := root.Query(tt.pattern, tt.predicates...)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", tt.pattern, err)
+		}
+		if got := queryPaths(matches); !reflect.DeepEqual(got, tt.exp) {
+			t.Errorf("expecting %s to match %v, got %v", tt.pattern, tt.exp, got)
+		}
+	}
+
+	if _, err := root.Query("deploy/[/canary"); err == nil {
+		t.Errorf("expecting an error for an invalid pattern")
+	}
+}
+
+func TestJob_GroupTaskRequest(t *testing.T) {
+	root := newQueryTree()
+	j := NewJob(root)
+
+	if err := j.TaskRequest(&pb.TaskRequest{Pattern: "deploy/*/canary", State: pb.TaskState_SKIPPED}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	matches, _ := j.Query("**", InState(pb.TaskState_SKIPPED))
+	if got, exp := queryPaths(matches), []string{"deploy/eu/canary", "deploy/us/canary"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("expecting %v to be skipped, got %v", exp, got)
+	}
+
+	err := j.TaskRequest(&pb.TaskRequest{Pattern: "deploy/*/canary", State: pb.TaskState_RUNNING})
+	var ite *IllegalTransitionError
+	if !errors.As(err, &ite) {
+		t.Errorf("expecting IllegalTransitionError, got %v", err)
+	}
+
+	if err := j.TaskRequest(&pb.TaskRequest{Pattern: "foo/*", State: pb.TaskState_SKIPPED}); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("expecting ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestRnrWebServer_Query(t *testing.T) {
+	root := newQueryTree()
+	root.Find([]string{"deploy", "us", "rest"}).(*Task).SetState(pb.TaskState_FAILED)
+	ws := NewRnrWebserver(NewJob(root))
+
+	rec := httptest.NewRecorder()
+	ws.queryHandler(rec, httptest.NewRequest(http.MethodGet, "/query?pattern=deploy/**&state=FAILED&state=SKIPPED&leaf=true", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var res struct {
+		Matches []struct {
+			Path []string
+			Task struct{ Name, State string }
+		}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Matches) != 1 || strings.Join(res.Matches[0].Path, "/") != "deploy/us/rest" || res.Matches[0].Task.State != "FAILED" {
+		t.Errorf("unexpected query result: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	ws.queryHandler(rec, httptest.NewRequest(http.MethodGet, "/query?state=FOO", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expecting status %d for an unknown state, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
@@ -0,0 +1,180 @@
+package rnr
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Resetter is implemented by tasks that need to be prepared before they can run again, such as ShellTask.
+type Resetter interface {
+	Reset()
+}
+
+type RetryOptions struct {
+	MaxAttempts    int                 // the maximum number of attempts, including the first one; defaults to 3.
+	InitialBackoff time.Duration       // the delay before the first retry; defaults to 1 second.
+	MaxBackoff     time.Duration       // the upper bound of the delay; unlimited if zero.
+	Multiplier     float64             // the delay is multiplied by this factor after each attempt; defaults to 2.
+	Jitter         float64             // up to this fraction of the delay is randomly added to it, i.e. 0.1 for 10%.
+	Retryable      func(*pb.Task) bool // decides whether a failed attempt should be retried; all failures are if nil.
+}
+
+// RetryTask runs its inner task and re-runs it with an exponential backoff if it fails.
+type RetryTask struct {
+	*Task
+	inner     TaskInterface
+	opts      RetryOptions
+	attempt   int
+	nextRetry time.Time
+	started   time.Time // the start of the current run of the RetryTask itself
+}
+
+// NewRetryTask returns a task retrying `inner`; the inner task becomes its only child and shares its name.
+func NewRetryTask(inner TaskInterface, opts RetryOptions) *RetryTask {
+	// Sanitize opts
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 3
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = time.Second
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = 2
+	}
+
+	ret := &RetryTask{
+		inner: inner,
+		opts:  opts,
+	}
+	ret.Task = NewTask(inner.Name(), true, ret.poll)
+	ret.Add(inner)
+	ret.updateStatus()
+
+	return ret
+}
+
+// Reset makes the next run start with the first attempt again.
+func (rt *RetryTask) Reset() {
+	rt.pollMu.Lock()
+	defer rt.pollMu.Unlock()
+
+	rt.started = time.Time{}
+	rt.attempt = 0
+	rt.nextRetry = time.Time{}
+}
+
+// backoff returns the delay before the attempt following `attempt`.
+func (rt *RetryTask) backoff(attempt int) time.Duration {
+	d := float64(rt.opts.InitialBackoff) * math.Pow(rt.opts.Multiplier, float64(attempt-1))
+	if rt.opts.MaxBackoff > 0 && d > float64(rt.opts.MaxBackoff) {
+		d = float64(rt.opts.MaxBackoff)
+	}
+	d += d * rt.opts.Jitter * rand.Float64()
+
+	return time.Duration(d)
+}
+
+// updateStatus publishes the retry status in the task's proto.
+func (rt *RetryTask) updateStatus() {
+	rt.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+		p.Retry = &pb.RetryStatus{
+			Attempt:     int32(rt.attempt),
+			MaxAttempts: int32(rt.opts.MaxAttempts),
+		}
+		if !rt.nextRetry.IsZero() {
+			p.Retry.NextRetry = timestamppb.New(rt.nextRetry)
+		}
+		return p
+	})
+}
+
+// start starts a new attempt of the inner task.
+func (rt *RetryTask) start() {
+	if taskSchedState(taskProto(rt.inner)) != PENDING {
+		if r, ok := rt.inner.(Resetter); ok {
+			r.Reset()
+		}
+		setState(rt.inner, pb.TaskState_PENDING, pb.TransitionSource_SOURCE_SCHEDULER)
+	}
+
+	rt.attempt++
+	rt.nextRetry = time.Time{}
+	setState(rt.inner, pb.TaskState_RUNNING, pb.TransitionSource_SOURCE_SCHEDULER)
+}
+
+func (rt *RetryTask) poll(ctx context.Context, task *Task) {
+	defer rt.updateStatus()
+
+	tpb := task.snapshot()
+	if tpb.State != pb.TaskState_RUNNING {
+		return
+	}
+
+	// Start counting the attempts from scratch if the RetryTask itself was rerun.
+	if started := tpb.Started.AsTime(); !started.Equal(rt.started) {
+		rt.started = started
+		rt.attempt = 0
+		rt.nextRetry = time.Time{}
+	}
+
+	if rt.attempt == 0 || taskSchedState(taskProto(rt.inner)) == PENDING {
+		rt.start()
+	}
+
+	rt.inner.Poll(ctx)
+
+	if rt.nextRetry.IsZero() || timeNow().Before(rt.nextRetry) || taskProto(rt.inner).State != pb.TaskState_FAILED {
+		rt.update(task)
+		return
+	}
+
+	// Backoff has passed, run another attempt
+	rt.start()
+	rt.inner.Poll(ctx)
+	rt.update(task)
+}
+
+// update updates the RetryTask's state based on the state of the current attempt.
+func (rt *RetryTask) update(task *Task) {
+	ipb := rt.inner.Proto(nil)
+	message := fmt.Sprintf("attempt %d/%d", rt.attempt, rt.opts.MaxAttempts)
+
+	switch ipb.State {
+	case pb.TaskState_SUCCESS, pb.TaskState_SKIPPED:
+		task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+			p.State = ipb.State
+			p.Message = ipb.Message
+			return p
+		})
+		return
+
+	case pb.TaskState_FAILED:
+		message = fmt.Sprintf("%s failed: %s", message, ipb.Message)
+
+		retryable := rt.opts.Retryable == nil || rt.opts.Retryable(ipb)
+		if rt.attempt >= rt.opts.MaxAttempts || !retryable {
+			task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+				p.State = pb.TaskState_FAILED
+				p.Message = message
+				return p
+			})
+			return
+		}
+
+		if rt.nextRetry.IsZero() {
+			rt.nextRetry = timeNow().Add(rt.backoff(rt.attempt))
+		}
+		message = fmt.Sprintf("%s; retrying at %s", message, rt.nextRetry.Format(time.RFC3339))
+	}
+
+	task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+		p.Message = message
+		return p
+	})
+}
@@ -0,0 +1,104 @@
+package rnr
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+func TestRetryTask_Backoff(t *testing.T) {
+	rt := NewRetryTask(newMockTask("mock", pb.TaskState_SUCCESS, nil), RetryOptions{
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Second,
+	})
+
+	for attempt, exp := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if got := rt.backoff(attempt + 1); got != exp {
+			t.Errorf("expecting backoff after attempt %d to be %v, got %v", attempt+1, exp, got)
+		}
+	}
+
+	rt.opts.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := rt.backoff(1); got < time.Second || got > 1500*time.Millisecond {
+			t.Fatalf("expecting jittered backoff to be within [1s, 1.5s], got %v", got)
+		}
+	}
+}
+
+func TestRetryTask_Retry(t *testing.T) {
+	ctx := context.TODO()
+	calls := 0
+	ct := NewCallbackTask("flaky", func(ctx context.Context, task *pb.Task) *pb.Task {
+		if task.State != pb.TaskState_RUNNING {
+			return task
+		}
+		calls++
+		if calls < 3 {
+			task.State = pb.TaskState_FAILED
+			task.Message = fmt.Sprintf("call %d failed", calls)
+		} else {
+			task.State = pb.TaskState_SUCCESS
+		}
+		return task
+	})
+	rt := NewRetryTask(ct, RetryOptions{MaxAttempts: 3, InitialBackoff: tick})
+	rt.SetState(pb.TaskState_RUNNING)
+
+	rt.Poll(ctx)
+	p := rt.Proto(nil)
+	compareTaskStates(t, []TaskInterface{ct, rt}, []pb.TaskState{pb.TaskState_FAILED, pb.TaskState_RUNNING})
+	if p.Retry.Attempt != 1 || p.Retry.MaxAttempts != 3 || p.Retry.NextRetry == nil {
+		t.Fatalf("unexpected retry status after the first attempt: %v", p.Retry)
+	}
+
+	// Backoff hasn't passed yet
+	rt.Poll(ctx)
+	if calls != 1 {
+		t.Fatalf("expecting the inner task not to be rerun during backoff, got %d calls", calls)
+	}
+
+	time.Sleep(tick)
+	rt.Poll(ctx)
+	if calls != 2 || rt.Proto(nil).Retry.Attempt != 2 {
+		t.Fatalf("expecting the second attempt to run, got %d calls (%v)", calls, rt.Proto(nil).Retry)
+	}
+
+	time.Sleep(3 * tick)
+	rt.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{ct, rt}, []pb.TaskState{pb.TaskState_SUCCESS, pb.TaskState_SUCCESS})
+	if p := rt.Proto(nil); p.Retry.Attempt != 3 || p.Retry.NextRetry != nil {
+		t.Errorf("unexpected retry status after success: %v", p.Retry)
+	}
+}
+
+func TestRetryTask_Exhausted(t *testing.T) {
+	ctx := context.TODO()
+	st := NewShellTask("shell", "false")
+	rt := NewRetryTask(st, RetryOptions{MaxAttempts: 2, InitialBackoff: time.Nanosecond})
+	rt.SetState(pb.TaskState_RUNNING)
+
+	for i := 0; i < 100 && rt.Proto(nil).State == pb.TaskState_RUNNING; i++ {
+		rt.Poll(ctx)
+		time.Sleep(time.Millisecond)
+	}
+
+	compareTaskStates(t, []TaskInterface{rt}, []pb.TaskState{pb.TaskState_FAILED})
+	if a := rt.Proto(nil).Retry.Attempt; a != 2 {
+		t.Errorf("expecting the shell task to be run 2 times, got %d", a)
+	}
+}
+
+func TestRetryTask_NotRetryable(t *testing.T) {
+	ctx := context.TODO()
+	rt := NewRetryTask(newMockTask("mock", pb.TaskState_FAILED, nil), RetryOptions{
+		Retryable: func(*pb.Task) bool { return false },
+	})
+	rt.SetState(pb.TaskState_RUNNING)
+
+	rt.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{rt}, []pb.TaskState{pb.TaskState_FAILED})
+}
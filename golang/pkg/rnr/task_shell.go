@@ -3,40 +3,165 @@ package rnr
 import (
 	"context"
 	"os/exec"
+	"sync"
 
 	"github.com/mplzik/rnr/golang/pkg/pb"
 )
 
-func NewShellTask(name, command string, args ...string) *Task {
-	cmd := exec.Command(command, args...)
-	err := make(chan error)
+// ShellTask runs a command once it's polled for the first time. The command's output is written to the task's log.
+// The command is killed once the task stops running (e.g. it's skipped or times out); pausing the task keeps it alive.
+type ShellTask struct {
+	*Task
+	command   string
+	args      []string
+	cmdMu     sync.Mutex
+	cmd       *exec.Cmd
+	started   bool
+	suspended bool
+	err       chan error
+}
 
-	return NewTask(name, false, func(ctx context.Context, task *Task) {
-		if cmd.Process == nil {
-			// Not yet started, let's launch it first
-			go func() { err <- cmd.Run() }()
-			task.Proto(func(taskpb *pb.Task) *pb.Task {
-				taskpb.Message = "Started"
-				return taskpb
-			})
+func NewShellTask(name, command string, args ...string) *ShellTask {
+	ret := &ShellTask{
+		command: command,
+		args:    args,
+		err:     make(chan error, 1),
+	}
+	ret.Task = NewTask(name, false, ret.poll)
+	ret.cmd = ret.newCommand()
+	ret.onCancel = ret.kill
+	ret.onInactive = ret.Reset
+	ret.activeIn = func(state pb.TaskState) bool {
+		return state == pb.TaskState_PAUSED || taskSchedState(&pb.Task{State: state}) == RUNNING
+	}
+	ret.SetTransitions(shellTaskTransitions)
+
+	return ret
+}
+
+// shellTaskTransitions don't allow rerunning the command once it has finished, as exec.Cmd can't be restarted.
+var shellTaskTransitions = Transitions{
+	pb.TaskState_UNKNOWN:       allStates,
+	pb.TaskState_PENDING:       allStates,
+	pb.TaskState_RUNNING:       allStates,
+	pb.TaskState_ACTION_NEEDED: allStates,
+	pb.TaskState_SUCCESS:       {pb.TaskState_FAILED, pb.TaskState_SKIPPED},
+	pb.TaskState_FAILED:        {pb.TaskState_SUCCESS, pb.TaskState_SKIPPED},
+	pb.TaskState_SKIPPED:       {pb.TaskState_SUCCESS, pb.TaskState_FAILED},
+}
+
+func (st *ShellTask) poll(ctx context.Context, task *Task) {
+	// Inactive tasks get a cancelled context; the command has been killed and mustn't be started again.
+	if ctx.Err() != nil || task.snapshot().State == pb.TaskState_PAUSED {
+		return
+	}
+
+	st.cmdMu.Lock()
+	if !st.started {
+		// Not yet started, let's launch it first
+		st.started = true
+		cmd, errCh := st.cmd, st.err
+		if err := cmd.Start(); err != nil {
+			errCh <- err
+		} else {
+			go func() {
+				err := cmd.Wait()
+				cmd.Stdout.(*logWriter).Flush()
+				errCh <- err
+			}()
 		}
+		task.Proto(func(taskpb *pb.Task) *pb.Task {
+			taskpb.Message = "Started"
+			return taskpb
+		})
+	}
+	errCh := st.err
+	st.cmdMu.Unlock()
 
-		select {
-		default:
-			// still running
-		case err := <-err:
-			task.Proto(func(taskpb *pb.Task) *pb.Task {
-				taskpb.Message = "Exited"
-				// The process has finished
-				if err != nil {
-					taskpb.State = pb.TaskState_FAILED
-					taskpb.Message = err.Error()
-				} else {
-					taskpb.State = pb.TaskState_SUCCESS
-				}
+	select {
+	default:
+		// still running
+	case err := <-errCh:
+		task.Proto(func(taskpb *pb.Task) *pb.Task {
+			if taskpb.State != pb.TaskState_RUNNING {
+				// The task was stopped in the meantime and the command killed; keep its state and message.
 				return taskpb
-			})
-		}
+			}
+			taskpb.Message = "Exited"
+			// The process has finished
+			if err != nil {
+				taskpb.State = pb.TaskState_FAILED
+				taskpb.Message = err.Error()
+			} else {
+				taskpb.State = pb.TaskState_SUCCESS
+			}
+			return taskpb
+		})
+	}
+
+}
+
+// Reset kills the command, if it's running, and prepares a fresh one to be run with the next poll.
+func (st *ShellTask) Reset() {
+	st.kill()
+
+	st.cmdMu.Lock()
+	defer st.cmdMu.Unlock()
+
+	st.cmd = st.newCommand()
+	st.err = make(chan error, 1)
+	st.started = false
+	st.suspended = false
+}
+
+// newCommand prepares the command to be run, logging its output.
+func (st *ShellTask) newCommand() *exec.Cmd {
+	cmd := exec.Command(st.command, st.args...)
+	w := &logWriter{task: st.Task}
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	return cmd
+}
+
+// kill kills the command, if it's running.
+func (st *ShellTask) kill() {
+	st.cmdMu.Lock()
+	defer st.cmdMu.Unlock()
+
+	if st.started && st.cmd.Process != nil {
+		st.cmd.Process.Kill()
+	}
+}
+
+// Suspend stops the command, if it's running, until Resume is called.
+func (st *ShellTask) Suspend() error {
+	st.cmdMu.Lock()
+	defer st.cmdMu.Unlock()
+
+	if !st.started || st.suspended || st.cmd.Process == nil {
+		return nil
+	}
+	if err := suspendProcess(st.cmd.Process); err != nil {
+		return err
+	}
+	st.suspended = true
+
+	return nil
+}
+
+// Resume continues the command stopped by Suspend.
+func (st *ShellTask) Resume() error {
+	st.cmdMu.Lock()
+	defer st.cmdMu.Unlock()
+
+	if !st.suspended {
+		return nil
+	}
+	if err := resumeProcess(st.cmd.Process); err != nil {
+		return err
+	}
+	st.suspended = false
 
-	})
+	return nil
 }
@@ -1,6 +1,12 @@
 package rnr
 
-import "testing"
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
 
 func TestShellTask_GetChild(t *testing.T) {
 	c := NewShellTask("shell task test", "").GetChild("foo")
@@ -9,3 +15,31 @@ func TestShellTask_GetChild(t *testing.T) {
 		t.Fatalf("expecting GetChild to return nil, got %#v", c)
 	}
 }
+
+func TestShellTask_KilledWhenStopped(t *testing.T) {
+	ctx := context.TODO()
+	st := NewShellTask("shell", "sleep", "10")
+	st.SetState(pb.TaskState_RUNNING)
+	st.Poll(ctx)
+	errCh := st.err
+
+	st.SetState(pb.TaskState_SKIPPED)
+
+	// The command should have been killed
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Errorf("expecting killed command to return an error")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("command was not killed")
+	}
+
+	st.Poll(ctx)
+	if p := st.Proto(nil); p.State != pb.TaskState_SKIPPED || p.Message != "Started" {
+		t.Errorf("expecting the task to stay skipped, got %v", p)
+	}
+	if st.started {
+		t.Errorf("expecting the command not to be started again")
+	}
+}
@@ -0,0 +1,16 @@
+package rnr
+
+import (
+	"errors"
+	"os"
+)
+
+var errSuspendNotSupported = errors.New("suspending processes is not supported on windows")
+
+func suspendProcess(p *os.Process) error {
+	return errSuspendNotSupported
+}
+
+func resumeProcess(p *os.Process) error {
+	return errSuspendNotSupported
+}
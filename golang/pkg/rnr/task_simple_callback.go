@@ -6,22 +6,32 @@ import (
 	"github.com/mplzik/rnr/golang/pkg/pb"
 )
 
+// CallbackFunc is called once per poll with a copy of the task's protobuf and returns the updated one, which is merged
+// with the changes made to the task (e.g. by an operator's request) while it ran, like a StateUpdateCallback.
 type CallbackFunc func(context.Context, *pb.Task) *pb.Task
 
+// CallbackTask calls the provided callback with each poll while it's running.
+type CallbackTask struct {
+	*Task
+	callback CallbackFunc
+}
+
 // NewCallbackTask returns a new callback task.
-func NewCallbackTask(name string, callback CallbackFunc) *Task {
-
-	newTask := NewTask(name, false, func(ctx context.Context, task *Task) {
-		var oldState = pb.TaskState_PENDING
-		task.Proto(func(taskState *pb.Task) *pb.Task {
-			if (taskState.State != pb.TaskState_RUNNING) && (oldState == taskState.State) {
-				return taskState
-			}
-
-			oldState = taskState.State
-			return callback(ctx, taskState)
-		})
-	})
+func NewCallbackTask(name string, callback CallbackFunc) *CallbackTask {
+	ret := &CallbackTask{
+		callback: callback,
+	}
+	ret.Task = NewTask(name, false, ret.poll)
 
-	return newTask
+	return ret
+}
+
+func (ct *CallbackTask) poll(ctx context.Context, task *Task) {
+	task.Proto(func(taskState *pb.Task) *pb.Task {
+		if taskState.State == pb.TaskState_PENDING {
+			return taskState
+		}
+
+		return ct.callback(ctx, taskState)
+	})
 }
@@ -110,3 +110,54 @@ func TestCallbackTask_GetChild(t *testing.T) {
 		t.Fatalf("expecting GetChild to return nil, got %#v", c)
 	}
 }
+
+func TestCallbackTask_CalledOncePerPoll(t *testing.T) {
+	ctx := context.TODO()
+
+	calls := 0
+	var ct *CallbackTask
+	ct = NewCallbackTask("callback", func(ctx context.Context, p *pb.Task) *pb.Task {
+		calls++
+		// Changes made while the callback runs don't make it run again; they're merged with its result.
+		if err := ct.SetOutput("calls", calls); err != nil {
+			t.Fatal(err)
+		}
+		ct.SetPriority(int32(calls))
+		p.Message = fmt.Sprintf("call %d", calls)
+		return p
+	})
+	ct.SetState(pb.TaskState_RUNNING)
+
+	for i := 1; i <= 3; i++ {
+		ct.Poll(ctx)
+
+		if calls != i {
+			t.Fatalf("expecting the callback to be called %d times, got %d", i, calls)
+		}
+		p := ct.Proto(nil)
+		if exp := fmt.Sprintf("call %d", i); p.Message != exp || p.Priority != int32(i) || p.Outputs["calls"].GetNumberValue() != float64(i) {
+			t.Errorf("unexpected protobuf %v", p)
+		}
+	}
+
+	t.Run("concurrent state change", func(t *testing.T) {
+		calls := 0
+		var ct *CallbackTask
+		ct = NewCallbackTask("callback", func(ctx context.Context, p *pb.Task) *pb.Task {
+			calls++
+			ct.SetState(pb.TaskState_SKIPPED)
+			p.State = pb.TaskState_SUCCESS
+			p.Message = "done"
+			return p
+		})
+		ct.SetState(pb.TaskState_RUNNING)
+		ct.Poll(ctx)
+
+		if calls != 1 {
+			t.Errorf("expecting the callback to be called once, got %d", calls)
+		}
+		if p := ct.Proto(nil); p.State != pb.TaskState_SKIPPED || p.Message != "" {
+			t.Errorf("expecting the callback's result to be dropped, got %v", p)
+		}
+	})
+}
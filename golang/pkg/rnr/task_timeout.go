@@ -0,0 +1,78 @@
+package rnr
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+// SetTimeout makes the task transition to `state` (typically FAILED or ACTION_NEEDED) once it has been running for
+// longer than `timeout`; zero disables the timeout. Tasks waiting for an action (ACTION_NEEDED) count as running, unless
+// `state` is ACTION_NEEDED. The task's context is cancelled on expiry.
+func (task *Task) SetTimeout(timeout time.Duration, state pb.TaskState) {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+
+	task.timeout = timeout
+	task.timeoutState = state
+}
+
+// SetDeadline makes the task transition to `state` (typically FAILED or ACTION_NEEDED) if it's still running at
+// `deadline`, like SetTimeout; zero time disables the deadline. The task's context is cancelled on expiry.
+func (task *Task) SetDeadline(deadline time.Time, state pb.TaskState) {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+
+	task.deadline = deadline
+	task.deadlineState = state
+}
+
+// expiry returns the time at which the running task expires along with the reason and the state it transitions to;
+// `ok` is false if it never does. The timeout or the deadline whose state the task is already in is ignored. Must be
+// called with `task.mu` held.
+func (task *Task) expiry() (at time.Time, reason string, state pb.TaskState, ok bool) {
+	consider := func(a time.Time, r string, s pb.TaskState) {
+		if s == pb.TaskState_UNKNOWN {
+			s = pb.TaskState_FAILED
+		}
+		if s == task.pb.State || (ok && !a.Before(at)) {
+			return
+		}
+		at, reason, state, ok = a, r, s, true
+	}
+
+	if task.timeout > 0 && task.pb.Started != nil {
+		consider(task.pb.Started.AsTime().Add(task.timeout), fmt.Sprintf("timed out after %s", task.timeout), task.timeoutState)
+	}
+	if !task.deadline.IsZero() {
+		consider(task.deadline, fmt.Sprintf("deadline %s exceeded", task.deadline.Format(time.RFC3339)), task.deadlineState)
+	}
+
+	return at, reason, state, ok
+}
+
+// checkExpiry checks whether the running task has expired; if so, it transitions the task to the configured state,
+// cancels it and returns true.
+func (task *Task) checkExpiry(now time.Time) bool {
+	task.mu.Lock()
+	at, reason, state, ok := task.expiry()
+	running := taskSchedState(task.pb) == RUNNING
+	task.mu.Unlock()
+
+	if !ok || !running || now.Before(at) {
+		return false
+	}
+
+	task.Proto(func(p *pb.Task) *pb.Task {
+		// The state might have changed in the meantime
+		if taskSchedState(p) == RUNNING && p.State != state {
+			p.State = state
+			p.Message = reason
+		}
+		return p
+	})
+	task.Cancel()
+
+	return true
+}
@@ -0,0 +1,113 @@
+package rnr
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+func TestTask_Timeout(t *testing.T) {
+	ctx := context.TODO()
+	calls := 0
+	hasDeadline := false
+	ct := NewCallbackTask("callback", func(ctx context.Context, task *pb.Task) *pb.Task {
+		calls++
+		_, hasDeadline = ctx.Deadline()
+		return task
+	})
+	ct.SetTimeout(tick, pb.TaskState_FAILED)
+	nt := NewNestedTask("nested", NestedTaskOptions{})
+	nt.Add(ct)
+	nt.SetState(pb.TaskState_RUNNING)
+
+	nt.Poll(ctx)
+	if calls != 1 || !hasDeadline {
+		t.Fatalf("expecting callback to be called once with a deadline, got %d calls (deadline: %t)", calls, hasDeadline)
+	}
+	compareTaskStates(t, []TaskInterface{ct, nt}, []pb.TaskState{pb.TaskState_RUNNING, pb.TaskState_RUNNING})
+
+	time.Sleep(2 * tick)
+	nt.Poll(ctx)
+
+	if calls != 1 {
+		t.Errorf("callback shouldn't be called after the timeout, got %d calls", calls)
+	}
+	compareTaskStates(t, []TaskInterface{ct, nt}, []pb.TaskState{pb.TaskState_FAILED, pb.TaskState_FAILED})
+	if msg := ct.Proto(nil).Message; !strings.Contains(msg, "timed out") {
+		t.Errorf("expecting message to explain the timeout, got %q", msg)
+	}
+}
+
+func TestTask_Deadline(t *testing.T) {
+	ctx := context.TODO()
+	st := NewShellTask("shell", "sleep", "10")
+	st.SetDeadline(time.Now().Add(tick), pb.TaskState_ACTION_NEEDED)
+	st.SetState(pb.TaskState_RUNNING)
+
+	st.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{st}, []pb.TaskState{pb.TaskState_RUNNING})
+
+	time.Sleep(2 * tick)
+	st.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{st}, []pb.TaskState{pb.TaskState_ACTION_NEEDED})
+	if msg := st.Proto(nil).Message; !strings.Contains(msg, "deadline") {
+		t.Errorf("expecting message to explain the deadline, got %q", msg)
+	}
+
+	// The command should have been killed
+	select {
+	case err := <-st.err:
+		if err == nil {
+			t.Errorf("expecting killed command to return an error")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("command was not killed")
+	}
+}
+
+func TestTask_TimeoutAndDeadline(t *testing.T) {
+	ctx := context.TODO()
+	ct := NewCallbackTask("callback", func(ctx context.Context, task *pb.Task) *pb.Task { return task })
+	ct.SetTimeout(tick, pb.TaskState_ACTION_NEEDED)
+	ct.SetDeadline(time.Now().Add(5*tick), pb.TaskState_FAILED)
+	ct.SetState(pb.TaskState_RUNNING)
+
+	ct.Poll(ctx)
+	time.Sleep(2 * tick)
+	ct.Poll(ctx)
+
+	// The timeout expires first, so its own state applies.
+	compareTaskStates(t, []TaskInterface{ct}, []pb.TaskState{pb.TaskState_ACTION_NEEDED})
+	if msg := ct.Proto(nil).Message; !strings.Contains(msg, "timed out") {
+		t.Errorf("expecting message to explain the timeout, got %q", msg)
+	}
+
+	// The task is still waiting for an action, so the deadline applies as well.
+	time.Sleep(4 * tick)
+	ct.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{ct}, []pb.TaskState{pb.TaskState_FAILED})
+	if msg := ct.Proto(nil).Message; !strings.Contains(msg, "deadline") {
+		t.Errorf("expecting message to explain the deadline, got %q", msg)
+	}
+}
+
+func TestTask_TimeoutActionNeeded(t *testing.T) {
+	ctx := context.TODO()
+	gate := NewApprovalTask("gate")
+	gate.SetTimeout(tick, pb.TaskState_FAILED)
+	gate.SetState(pb.TaskState_RUNNING)
+
+	gate.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{gate}, []pb.TaskState{pb.TaskState_ACTION_NEEDED})
+
+	// A gate nobody decides on times out like any running task.
+	time.Sleep(2 * tick)
+	gate.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{gate}, []pb.TaskState{pb.TaskState_FAILED})
+	if msg := gate.Proto(nil).Message; !strings.Contains(msg, "timed out") {
+		t.Errorf("expecting message to explain the timeout, got %q", msg)
+	}
+}
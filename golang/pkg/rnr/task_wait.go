@@ -0,0 +1,137 @@
+package rnr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	ErrNotAWaitTask = errors.New("task is not a wait task")
+	ErrNotWaiting   = errors.New("task is not waiting")
+	ErrInvalidWait  = errors.New("invalid wait request")
+)
+
+// Waiter is implemented by tasks waiting for some time to pass, such as WaitTask.
+type Waiter interface {
+	// SkipWait stops waiting right away.
+	SkipWait() error
+	// ExtendWait makes the task wait `d` longer; `d` must be positive.
+	ExtendWait(d time.Duration) error
+}
+
+// WaitTask succeeds once a given time has passed, e.g. to let a change bake before proceeding further. The time is
+// checked against the wall clock, so the task stays correct regardless of how often it's polled; it can only finish
+// late by up to one poll interval.
+//
+// The end of the wait is published in the task's protobuf as `wait_until`, along with the remaining time in the
+// message. Operators can skip or extend the wait (see Waiter).
+type WaitTask struct {
+	*Task
+	duration time.Duration // the time to wait after the task starts, unless `until` is set
+	until    time.Time
+	started  time.Time // the start of the run the wait has been set up for
+}
+
+// NewSleepTask returns a task waiting for `d` after it starts.
+func NewSleepTask(name string, d time.Duration) *WaitTask {
+	ret := &WaitTask{duration: d}
+	ret.Task = NewTask(name, false, ret.poll)
+
+	return ret
+}
+
+// NewWaitUntilTask returns a task waiting until `t`.
+func NewWaitUntilTask(name string, t time.Time) *WaitTask {
+	ret := &WaitTask{until: t}
+	ret.Task = NewTask(name, false, ret.poll)
+
+	return ret
+}
+
+func (wt *WaitTask) poll(ctx context.Context, task *Task) {
+	tpb := task.snapshot()
+	if tpb.State != pb.TaskState_RUNNING {
+		return
+	}
+
+	// Set up the wait again if the task was rerun.
+	started := tpb.Started.AsTime()
+	newRun := !started.Equal(wt.started)
+	wt.started = started
+
+	now := timeNow()
+	task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+		if p.State != pb.TaskState_RUNNING {
+			return p
+		}
+		if newRun || p.WaitUntil == nil {
+			until := wt.until
+			if until.IsZero() {
+				until = started.Add(wt.duration)
+			}
+			p.WaitUntil = timestamppb.New(until)
+		}
+
+		until := p.WaitUntil.AsTime()
+		if now.Before(until) {
+			p.Message = fmt.Sprintf("%s remaining", until.Sub(now).Round(time.Second))
+		} else {
+			p.State = pb.TaskState_SUCCESS
+			p.Message = fmt.Sprintf("waited until %s", until.Format(time.RFC3339))
+		}
+		return p
+	})
+}
+
+// SkipWait makes the task succeed right away; it's only allowed while the task is waiting.
+func (wt *WaitTask) SkipWait() error {
+	now := timeNow()
+
+	var err error
+	wt.updateFrom(pb.TransitionSource_SOURCE_REQUEST, func(p *pb.Task) *pb.Task {
+		err = nil
+		if p.State != pb.TaskState_RUNNING || p.WaitUntil == nil {
+			err = fmt.Errorf("%w: task '%s' is %s", ErrNotWaiting, p.Name, p.State)
+			return p
+		}
+
+		p.WaitUntil = timestamppb.New(now)
+		p.State = pb.TaskState_SUCCESS
+		p.Message = "wait skipped"
+		return p
+	})
+	if err == nil {
+		wt.Logf("wait skipped")
+	}
+
+	return err
+}
+
+// ExtendWait makes the task wait `d` longer; it's only allowed while the task is waiting, and `d` must be positive.
+func (wt *WaitTask) ExtendWait(d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("%w: can't extend the wait by %s", ErrInvalidWait, d)
+	}
+
+	var err error
+	wt.updateFrom(pb.TransitionSource_SOURCE_REQUEST, func(p *pb.Task) *pb.Task {
+		err = nil
+		if p.State != pb.TaskState_RUNNING || p.WaitUntil == nil {
+			err = fmt.Errorf("%w: task '%s' is %s", ErrNotWaiting, p.Name, p.State)
+			return p
+		}
+
+		p.WaitUntil = timestamppb.New(p.WaitUntil.AsTime().Add(d))
+		return p
+	})
+	if err == nil {
+		wt.Logf("wait extended by %s", d)
+	}
+
+	return err
+}
@@ -0,0 +1,129 @@
+package rnr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+// fakeClock makes timeNow return a time controlled by the test.
+type fakeClock struct {
+	now time.Time
+}
+
+func newFakeClock(t *testing.T) *fakeClock {
+	c := &fakeClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	timeNow = func() time.Time { return c.now }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	return c
+}
+
+func TestWaitTask_Sleep(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock(t)
+	wt := NewSleepTask("bake", 10*time.Minute)
+
+	wt.SetState(pb.TaskState_RUNNING)
+	wt.Poll(ctx)
+
+	if got := wt.Proto(nil).Message; got != "10m0s remaining" {
+		t.Errorf("unexpected message %q", got)
+	}
+	if until := wt.Proto(nil).WaitUntil.AsTime(); !until.Equal(clock.now.Add(10 * time.Minute)) {
+		t.Errorf("unexpected wait_until %v", until)
+	}
+
+	// A single late poll is enough to finish.
+	clock.now = clock.now.Add(15 * time.Minute)
+	wt.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{wt}, []pb.TaskState{pb.TaskState_SUCCESS})
+
+	// A rerun waits again.
+	wt.SetState(pb.TaskState_PENDING)
+	wt.SetState(pb.TaskState_RUNNING)
+	wt.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{wt}, []pb.TaskState{pb.TaskState_RUNNING})
+	if until := wt.Proto(nil).WaitUntil.AsTime(); !until.Equal(clock.now.Add(10 * time.Minute)) {
+		t.Errorf("unexpected wait_until after a rerun %v", until)
+	}
+}
+
+func TestWaitTask_ExtendAndSkip(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock(t)
+	wt := NewWaitUntilTask("window", clock.now.Add(time.Hour))
+
+	if err := wt.ExtendWait(time.Minute); !errors.Is(err, ErrNotWaiting) {
+		t.Errorf("expecting ErrNotWaiting before the task started, got %v", err)
+	}
+
+	wt.SetState(pb.TaskState_RUNNING)
+	wt.Poll(ctx)
+
+	for _, d := range []time.Duration{0, -time.Minute} {
+		if err := wt.ExtendWait(d); !errors.Is(err, ErrInvalidWait) {
+			t.Errorf("expecting ErrInvalidWait when extending by %s, got %v", d, err)
+		}
+	}
+	if err := wt.ExtendWait(30 * time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clock.now = clock.now.Add(time.Hour)
+	wt.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{wt}, []pb.TaskState{pb.TaskState_RUNNING})
+	if got := wt.Proto(nil).Message; got != "30m0s remaining" {
+		t.Errorf("unexpected message %q", got)
+	}
+
+	if err := wt.SkipWait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	compareTaskStates(t, []TaskInterface{wt}, []pb.TaskState{pb.TaskState_SUCCESS})
+	if err := wt.SkipWait(); !errors.Is(err, ErrNotWaiting) {
+		t.Errorf("expecting ErrNotWaiting after the wait is over, got %v", err)
+	}
+}
+
+func TestRnrWebServer_Wait(t *testing.T) {
+	newFakeClock(t)
+	nt := NewNestedTask("root", NestedTaskOptions{})
+	wt := NewSleepTask("bake", time.Hour)
+	nt.Add(wt)
+	nt.Add(newMockTask("other", pb.TaskState_SUCCESS, nil))
+	ws := NewRnrWebserver(NewJob(nt))
+
+	wt.SetState(pb.TaskState_RUNNING)
+	wt.Poll(context.Background())
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"path": ["other"], "skip": true}`, http.StatusBadRequest},
+		{`{"path": ["nope"], "skip": true}`, http.StatusNotFound},
+		{`{"path": ["bake"]}`, http.StatusBadRequest},
+		{`{"path": ["bake"], "extend": "0s"}`, http.StatusBadRequest},
+		{`{"path": ["bake"], "extend": "-600s"}`, http.StatusBadRequest},
+		{`{"path": ["bake"], "extend": "600s"}`, http.StatusOK},
+		{`{"path": ["bake"], "skip": true}`, http.StatusOK},
+		{`{"path": ["bake"], "skip": true}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		ws.waitHandler(rec, httptest.NewRequest(http.MethodPost, "/wait", strings.NewReader(tt.body)))
+		if rec.Code != tt.code {
+			t.Errorf("expecting status %d for %s, got %d", tt.code, tt.body, rec.Code)
+		}
+	}
+
+	if got := logTexts(wt.Logs(0)); len(got) != 2 || got[0] != "wait extended by 10m0s" || got[1] != "wait skipped" {
+		t.Errorf("expecting the actions to be logged, got %v", got)
+	}
+}
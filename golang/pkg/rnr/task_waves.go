@@ -0,0 +1,188 @@
+package rnr
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+// Wave is a step of a gradual rollout done by a NestedTask. Each wave releases more children, up to the given count or
+// percentage of all of them (whichever is larger), picking the first pending ones in the NestedTask's Order, e.g.
+//
+//	Waves: []Wave{{Count: 1}, {Percent: 5}, {Percent: 25}, {Percent: 100}}
+//
+// releases a single canary, then 5%, 25% and finally all the children. The children not covered by any wave are
+// released by an implicit last wave. A wave is released once all the children of the previous one are done, the
+// BakeTime has passed and the HealthCheck has passed. Released children stay released, so reordering the children
+// (e.g. bumping their priority) only affects the waves yet to be released.
+//
+// Rerunning the NestedTask resumes from the last wave that is done, baking it and checking the health again.
+type Wave struct {
+	Count   int
+	Percent float64
+}
+
+type waveState struct {
+	started   time.Time       // the start of the run the waves are tracked for
+	wave      int             // the index of the last released wave
+	count     int             // the number of waves
+	released  map[string]bool // the names of the children released so far
+	bakeUntil time.Time       // the end of the bake time of the last released wave, once it's done
+}
+
+// waveSizes returns the number of children released by each wave, including the implicit last one.
+func waveSizes(waves []Wave, total int) []int {
+	var ret []int
+	prev := 0
+	for _, w := range waves {
+		n := w.Count
+		if p := int(math.Ceil(w.Percent * float64(total) / 100)); p > n {
+			n = p
+		}
+		if n > total {
+			n = total
+		}
+		if n <= prev {
+			continue
+		}
+		ret = append(ret, n)
+		prev = n
+	}
+	if prev < total {
+		ret = append(ret, total)
+	}
+
+	return ret
+}
+
+// release releases the first children that haven't been released yet until `n` of them are, and returns the released
+// ones.
+func (ws *waveState) release(children []TaskInterface, n int) []TaskInterface {
+	if ws.released == nil {
+		ws.released = map[string]bool{}
+	}
+
+	var ret []TaskInterface
+	for _, child := range children {
+		if ws.released[child.Name()] {
+			ret = append(ret, child)
+		}
+	}
+	for _, child := range children {
+		if len(ret) >= n {
+			break
+		}
+		if name := child.Name(); !ws.released[name] {
+			ws.released[name] = true
+			ret = append(ret, child)
+		}
+	}
+
+	return ret
+}
+
+// childrenDone returns whether all the `children` are done.
+func childrenDone(children []TaskInterface) bool {
+	for _, child := range children {
+		if taskSchedState(taskProto(child)) != DONE {
+			return false
+		}
+	}
+
+	return true
+}
+
+// releasedChildren returns the names of the children released so far, releasing the next wave once it's due; the
+// children are released in the order of `children`. It returns false if the NestedTask has failed its health check.
+func (nt *NestedTask) releasedChildren(ctx context.Context, task *Task, children []TaskInterface) (map[string]bool, bool) {
+	opts := nt.opts
+	sizes := waveSizes(opts.Waves, len(children))
+	if len(sizes) == 0 {
+		return nil, true
+	}
+
+	// Resume from the last wave that is done if the NestedTask was rerun; the children that are done count as released.
+	if started := task.snapshot().Started.AsTime(); !started.Equal(nt.waves.started) {
+		nt.waves = waveState{started: started, released: map[string]bool{}}
+		done := 0
+		for _, child := range children {
+			if taskSchedState(taskProto(child)) == DONE {
+				nt.waves.released[child.Name()] = true
+				done++
+			}
+		}
+		for nt.waves.wave < len(sizes)-1 && done >= sizes[nt.waves.wave+1] {
+			nt.waves.wave++
+		}
+	}
+	nt.waves.count = len(sizes)
+	if nt.waves.wave >= len(sizes) {
+		nt.waves.wave = len(sizes) - 1
+	}
+
+	wave := nt.waves.wave
+	released := nt.waves.release(children, sizes[wave])
+	if wave == len(sizes)-1 || !childrenDone(released) {
+		return nt.waves.released, true
+	}
+
+	// Don't release more children once the failure budget is exceeded; Poll fails the task right away.
+	failed := 0
+	for _, child := range children {
+		if taskProto(child).State == pb.TaskState_FAILED {
+			failed++
+		}
+	}
+	if nt.failureBudgetExceeded(failed, len(children)) {
+		return nt.waves.released, true
+	}
+
+	if opts.BakeTime > 0 {
+		now := timeNow()
+		if nt.waves.bakeUntil.IsZero() {
+			nt.waves.bakeUntil = now.Add(opts.BakeTime)
+			task.Logf("wave %d/%d done, baking for %s", wave+1, len(sizes), opts.BakeTime)
+		}
+		if now.Before(nt.waves.bakeUntil) {
+			return nt.waves.released, true
+		}
+	}
+
+	if opts.HealthCheck != nil {
+		if err := opts.HealthCheck(ctx, task); err != nil {
+			task.Logf("health check after wave %d/%d failed: %s", wave+1, len(sizes), err.Error())
+			task.updateFrom(pb.TransitionSource_SOURCE_TASK, func(p *pb.Task) *pb.Task {
+				p.State = pb.TaskState_FAILED
+				p.Message = fmt.Sprintf("health check after wave %d/%d failed: %s", wave+1, len(sizes), err.Error())
+				return p
+			})
+			return nt.waves.released, false
+		}
+	}
+
+	nt.waves.wave++
+	nt.waves.bakeUntil = time.Time{}
+	next := nt.waves.release(children, sizes[wave+1])
+	task.Logf("releasing wave %d/%d: %d tasks", wave+2, len(sizes), len(next)-len(released))
+
+	return nt.waves.released, true
+}
+
+// waveStatus returns the progress of the waves to be shown in the task's message.
+func (nt *NestedTask) waveStatus() string {
+	if nt.waves.count == 0 {
+		return ""
+	}
+
+	ret := fmt.Sprintf(", wave %d/%d", nt.waves.wave+1, nt.waves.count)
+	if !nt.waves.bakeUntil.IsZero() {
+		if remaining := nt.waves.bakeUntil.Sub(timeNow()); remaining > 0 {
+			ret += fmt.Sprintf(", baking %s", remaining.Round(time.Second))
+		}
+	}
+
+	return ret
+}
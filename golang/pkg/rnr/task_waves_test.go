@@ -0,0 +1,211 @@
+package rnr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+func TestWaveSizes(t *testing.T) {
+	canary := []Wave{{Count: 1}, {Percent: 5}, {Percent: 25}, {Percent: 100}}
+
+	for _, tc := range []struct {
+		waves []Wave
+		total int
+		want  []int
+	}{
+		{canary, 100, []int{1, 5, 25, 100}},
+		{canary, 10, []int{1, 3, 10}},
+		{canary, 1, []int{1}},
+		{canary, 0, nil},
+		{[]Wave{{Count: 2}}, 10, []int{2, 10}},
+		{[]Wave{{Count: 5, Percent: 10}}, 100, []int{10, 100}},
+	} {
+		if got := waveSizes(tc.waves, tc.total); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("waveSizes(%v, %d) = %v, want %v", tc.waves, tc.total, got, tc.want)
+		}
+	}
+}
+
+// newWaveTask returns a NestedTask with `states` as the final states of its children.
+func newWaveTask(opts NestedTaskOptions, states ...pb.TaskState) *NestedTask {
+	nt := NewNestedTask("rollout", opts)
+	for i, state := range states {
+		nt.Add(newMockTask(fmt.Sprintf("host %d", i), state, nil))
+	}
+	nt.SetState(pb.TaskState_RUNNING)
+
+	return nt
+}
+
+// countStates returns the number of the task's children in `state`.
+func countStates(task *Task, state pb.TaskState) int {
+	ret := 0
+	for _, child := range task.Children() {
+		if child.Proto(nil).State == state {
+			ret++
+		}
+	}
+
+	return ret
+}
+
+func TestNestedTask_Waves(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock(t)
+	checks := 0
+	states := make([]pb.TaskState, 10)
+	for i := range states {
+		states[i] = pb.TaskState_SUCCESS
+	}
+	nt := newWaveTask(NestedTaskOptions{
+		Parallelism: 10,
+		Waves:       []Wave{{Count: 1}, {Percent: 50}},
+		BakeTime:    10 * time.Minute,
+		HealthCheck: func(ctx context.Context, task *Task) error {
+			checks++
+			return nil
+		},
+	}, states...)
+
+	// The canary runs alone.
+	nt.Poll(ctx)
+	if got := countStates(nt.Task, pb.TaskState_SUCCESS); got != 1 {
+		t.Fatalf("expected 1 finished child after the first wave, got %d", got)
+	}
+
+	// Then it bakes.
+	nt.Poll(ctx)
+	if got := nt.Proto(nil).Message; got != "1/10, wave 1/3, baking 10m0s" {
+		t.Errorf("unexpected message %q", got)
+	}
+	if checks != 0 {
+		t.Errorf("health check shouldn't run while baking")
+	}
+
+	clock.now = clock.now.Add(10 * time.Minute)
+	nt.Poll(ctx)
+	if got := countStates(nt.Task, pb.TaskState_SUCCESS); got != 5 {
+		t.Fatalf("expected 5 finished children after the second wave, got %d", got)
+	}
+	if checks != 1 {
+		t.Errorf("expected 1 health check, got %d", checks)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	nt.Poll(ctx)
+	if got := nt.Proto(nil).Message; got != "5/10, wave 2/3, baking 10m0s" {
+		t.Errorf("unexpected message %q", got)
+	}
+
+	clock.now = clock.now.Add(10 * time.Minute)
+	nt.Poll(ctx)
+	nt.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{nt}, []pb.TaskState{pb.TaskState_SUCCESS})
+	if checks != 2 {
+		t.Errorf("expected 2 health checks, got %d", checks)
+	}
+	if got := logTexts(nt.Logs(0)); len(got) != 4 || got[1] != "releasing wave 2/3: 4 tasks" {
+		t.Errorf("unexpected logs %q", got)
+	}
+}
+
+func TestNestedTask_WavesReordered(t *testing.T) {
+	ctx := context.Background()
+	nt := newWaveTask(NestedTaskOptions{
+		Parallelism: 10,
+		Order:       ByPriority,
+		Waves:       []Wave{{Count: 1}, {Count: 2}, {Count: 3}},
+	}, pb.TaskState_SUCCESS, pb.TaskState_SUCCESS, pb.TaskState_SUCCESS, pb.TaskState_SUCCESS, pb.TaskState_SUCCESS)
+
+	nt.Poll(ctx)
+	if got := countStates(nt.Task, pb.TaskState_SUCCESS); got != 1 {
+		t.Fatalf("expected 1 finished child after the first wave, got %d", got)
+	}
+
+	// Bumping the pending children mid-rollout doesn't make the waves release more of them.
+	nt.GetChild("host 3").(*Task).SetPriority(10)
+	nt.GetChild("host 4").(*Task).SetPriority(10)
+	for i, exp := range []int{2, 3, 5} {
+		nt.Poll(ctx)
+		if got := countStates(nt.Task, pb.TaskState_SUCCESS); got != exp {
+			t.Fatalf("expected %d finished children after wave %d, got %d", exp, i+2, got)
+		}
+		if i == 0 {
+			// But the bumped children go first.
+			compareTaskStates(t, nt.Children()[3:], []pb.TaskState{pb.TaskState_SUCCESS, pb.TaskState_PENDING})
+		}
+	}
+}
+
+func TestNestedTask_WavesFailureBudget(t *testing.T) {
+	ctx := context.Background()
+	states := []pb.TaskState{pb.TaskState_FAILED, pb.TaskState_FAILED}
+	for i := 0; i < 8; i++ {
+		states = append(states, pb.TaskState_SUCCESS)
+	}
+
+	// Two failures are within the budget, so the rollout goes on.
+	nt := newWaveTask(NestedTaskOptions{
+		Parallelism: 10,
+		Waves:       []Wave{{Count: 2}},
+		MaxFailures: 2,
+	}, states...)
+	nt.Poll(ctx)
+	nt.Poll(ctx)
+	nt.Poll(ctx)
+	if got := countStates(nt.Task, pb.TaskState_SUCCESS); got != 8 {
+		t.Errorf("expected 8 finished children, got %d", got)
+	}
+	compareTaskStates(t, []TaskInterface{nt}, []pb.TaskState{pb.TaskState_SUCCESS})
+
+	// 20% is over the budget.
+	nt = newWaveTask(NestedTaskOptions{
+		Parallelism:       10,
+		Waves:             []Wave{{Count: 2}},
+		MaxFailurePercent: 10,
+	}, states...)
+	nt.Poll(ctx)
+	nt.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{nt}, []pb.TaskState{pb.TaskState_FAILED})
+	if got := countStates(nt.Task, pb.TaskState_PENDING); got != 8 {
+		t.Errorf("expected 8 children to stay pending, got %d", got)
+	}
+	if got := nt.Proto(nil).Message; !strings.HasSuffix(got, "failure budget exceeded") {
+		t.Errorf("unexpected message %q", got)
+	}
+}
+
+func TestNestedTask_WavesHealthCheckFailed(t *testing.T) {
+	ctx := context.Background()
+	healthy := false
+	nt := newWaveTask(NestedTaskOptions{
+		Waves: []Wave{{Count: 1}},
+		HealthCheck: func(ctx context.Context, task *Task) error {
+			if !healthy {
+				return errors.New("error rate too high")
+			}
+			return nil
+		},
+	}, pb.TaskState_SUCCESS, pb.TaskState_SUCCESS)
+
+	nt.Poll(ctx)
+	nt.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{nt}, []pb.TaskState{pb.TaskState_FAILED})
+	if got := nt.Proto(nil).Message; got != "health check after wave 1/2 failed: error rate too high" {
+		t.Errorf("unexpected message %q", got)
+	}
+
+	// A rerun checks the health of the last wave again before continuing.
+	healthy = true
+	nt.SetState(pb.TaskState_RUNNING)
+	nt.Poll(ctx)
+	nt.Poll(ctx)
+	compareTaskStates(t, []TaskInterface{nt}, []pb.TaskState{pb.TaskState_SUCCESS})
+}
@@ -5,9 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/mplzik/rnr/golang/pkg/pb"
 	proto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type TaskState int
@@ -17,6 +22,7 @@ const (
 	PENDING
 	RUNNING
 	DONE
+	PAUSED
 )
 
 var ErrNoChildrenAllowed = errors.New("no children allowed for this kind of task")
@@ -31,6 +37,9 @@ func taskSchedState(pbt *pb.Task) TaskState {
 
 	case pb.TaskState_ACTION_NEEDED, pb.TaskState_RUNNING:
 		return RUNNING
+
+	case pb.TaskState_PAUSED:
+		return PAUSED
 	}
 
 	return UNKNOWN
@@ -38,65 +47,339 @@ func taskSchedState(pbt *pb.Task) TaskState {
 
 // TaskCallback is installed by higher-level construct, such as callback task or nested task.
 type TaskCallback func(context.Context, *Task)
+
+// StateUpdateCallback modifies a copy of a task's protobuf and returns the new one. It's called once, without holding
+// the task's lock; the fields it changes are merged with any concurrent changes to the task, which take precedence.
+// If the task's state changes concurrently, the update is dropped.
 type StateUpdateCallback func(*pb.Task) *pb.Task
 
+// TaskInterface is implemented by anything that can be scheduled by a Job or a NestedTask.
+//
+// Custom task types usually embed a *Task created using NewTask and pass their own poll method as the callback,
+// which provides the state handling, children management and synchronization for free. Some features are only
+// available to the task types embedding a *Task, as they rely on its unexported methods; other implementations still
+// work, but:
+//   - the scheduler's and the operators' updates reach them through Proto, without the source of the change;
+//   - ParentPath segments in Lookup and OutputOf paths can't lead out of them or their children;
+//   - they have no parameters and keep no log;
+//   - they're copied by each Proto call instead of being cached, and so are their ancestors' snapshots.
+type TaskInterface interface {
+	// Name returns the task's name; it must not change during the task's lifetime.
+	Name() string
+	// Poll gives the task an opportunity to do its work and update its state.
+	Poll(context.Context)
+	// Proto optionally updates the task's protobuf using the provided callback and returns its copy.
+	Proto(StateUpdateCallback) *pb.Task
+	// Children returns the task's children, if any.
+	Children() []TaskInterface
+	// Cancel stops any background work associated with the task and its children.
+	Cancel()
+}
+
 // Task is a generic interface for pollable tasks
+//
+// A Task is safe for concurrent use; `mu` guards the protobuf, the children
+// list with its name index and the cached snapshot, while `pollMu` serializes invocations of the callback.
 type Task struct {
-	cb           TaskCallback
-	pb           *pb.Task
-	children     []*Task
-	has_children bool
+	mu            sync.Mutex
+	pollMu        sync.Mutex
+	cb            TaskCallback
+	onCancel      func()                  // stops the background work of built-in task types
+	onInactive    func()                  // likewise, called once the task stops being active
+	activeIn      func(pb.TaskState) bool // the states in which the task's context is kept alive
+	runCtx        context.Context
+	runCancel     context.CancelFunc
+	transitions   Transitions
+	timeout       time.Duration
+	deadline      time.Time
+	timeoutState  pb.TaskState
+	deadlineState pb.TaskState
+	pb            *pb.Task
+	version       uint64    // bumped with every change to `pb`
+	lastPolled    time.Time // kept out of `pb`, so that polls don't invalidate the snapshots
+	children      []TaskInterface
+	index         map[string]int // child name -> position in `children`
+	has_children  bool
+	parent        atomic.Value // *Task; notified about changes to invalidate its snapshot
+	dirty         int32        // accessed atomically; set when `cache` is out of date
+	cache         *pb.Task     // an immutable snapshot of the task and its children
+	logs          taskLog
+	params        map[string]ParamSpec
 }
 
 func NewTask(name string, children bool, cb TaskCallback) *Task {
 	return &Task{
 		cb: cb,
 		pb: &pb.Task{
-			Name:  name,
-			State: pb.TaskState_PENDING,
+			Name:    name,
+			State:   pb.TaskState_PENDING,
+			Created: timestamppb.New(timeNow()),
 		},
-		children:     []*Task{},
+		children:     []TaskInterface{},
+		index:        map[string]int{},
 		has_children: children,
+		dirty:        1,
 	}
 }
 
 func (task *Task) Poll(ctx context.Context) {
-	task.cb(ctx, task)
+	task.pollMu.Lock()
+	defer task.pollMu.Unlock()
+	defer task.recoverPanic()
+
+	now := timeNow()
+
+	task.mu.Lock()
+	task.lastPolled = now
+	task.mu.Unlock()
+
+	if task.checkExpiry(now) {
+		return
+	}
+
+	if task.cb != nil {
+		task.cb(task.context(ctx), task)
+	}
 }
 
+// Proto optionally updates the task's protobuf using `updater` and returns a copy of it, including the children.
+// The returned protobuf is owned by the caller.
 func (task *Task) Proto(updater StateUpdateCallback) *pb.Task {
+	task.mergeFrom(pb.TransitionSource_SOURCE_TASK, updater)
+
+	ret, ok := proto.Clone(task.snapshot()).(*pb.Task)
+	if !ok {
+		log.Fatalf("Failed to clone proto")
+	}
+	task.attachVolatile(ret, timeNow())
+
+	return ret
+}
+
+// sourcedUpdater records the originator of an update in the task's history.
+type sourcedUpdater interface {
+	updateFrom(pb.TransitionSource, StateUpdateCallback)
+}
+
+// updateProto updates a task's protobuf, recording `source` in the task's history if the task supports it.
+func updateProto(task TaskInterface, source pb.TransitionSource, updater StateUpdateCallback) {
+	if t, ok := task.(sourcedUpdater); ok {
+		t.updateFrom(source, updater)
+		return
+	}
+
+	task.Proto(updater)
+}
+
+// updateFrom updates the task's protobuf using `updater`, recording `source` as the originator of any state transitions.
+// Unlike Proto, it doesn't copy the task's subtree.
+//
+// The updater runs on a copy of the protobuf without holding the task's lock, so that it can read the task tree
+// (including the task itself) and the readers aren't blocked by long-running callbacks. If the protobuf is changed by
+// someone else in the meantime, the updater is called again with the new one, so it must not have side effects; user
+// code goes through mergeFrom instead. Updates that don't change anything keep the cached snapshots.
+func (task *Task) updateFrom(source pb.TransitionSource, updater StateUpdateCallback) {
+	if updater == nil {
+		return
+	}
+
+	for {
+		task.mu.Lock()
+		base, version := task.pb, task.version
+		task.mu.Unlock()
+
+		newState := updater(cloneTask(base))
+
+		task.mu.Lock()
+		if task.version != version {
+			task.mu.Unlock()
+			continue
+		}
+		changed, stopped := task.commit(source, newState)
+		task.mu.Unlock()
+		task.afterCommit(changed, stopped)
+
+		return
+	}
+}
+
+// mergeFrom is like updateFrom, but calls `updater` exactly once. If the protobuf is changed by someone else in the
+// meantime, the fields changed by the updater are merged into the new protobuf, with the concurrent changes taking
+// precedence; if the task's state itself has changed, the update is dropped, as it was based on a state the task has
+// already left.
+func (task *Task) mergeFrom(source pb.TransitionSource, updater StateUpdateCallback) {
+	if updater == nil {
+		return
+	}
+
+	task.mu.Lock()
+	base, version := task.pb, task.version
+	task.mu.Unlock()
+
+	newState := updater(cloneTask(base))
+
+	task.mu.Lock()
+	if task.version != version {
+		if task.pb.State != base.State {
+			task.mu.Unlock()
+			return
+		}
+		newState = mergeChanges(base, newState, task.pb)
+	}
+	changed, stopped := task.commit(source, newState)
+	task.mu.Unlock()
+	task.afterCommit(changed, stopped)
+}
+
+// commit replaces the task's protobuf with `newState` and reports whether anything has changed and whether the task
+// has stopped being active. It must be called with `task.mu` held; `task.pb` is never modified in place, so that the
+// updaters can use it as their base.
+func (task *Task) commit(source pb.TransitionSource, newState *pb.Task) (changed, stopped bool) {
+	if proto.Equal(task.pb, newState) {
+		return false, false
+	}
+	prevState := task.pb.State
+	task.pb = newState
+	task.version++
+	now := timeNow()
+	updateLifecycle(prevState, task.pb, now)
+	recordTransition(prevState, task.pb, source, now)
+	if !task.isActive(task.pb.State) {
+		task.cancelContext()
+		stopped = task.isActive(prevState)
+	}
+
+	return true, stopped
+}
+
+// afterCommit invalidates the snapshots after a change made by commit and stops the task's background work if the task
+// has stopped being active. It must be called without holding `task.mu`.
+func (task *Task) afterCommit(changed, stopped bool) {
+	if !changed {
+		return
+	}
+	task.markDirty()
+	if stopped && task.onInactive != nil {
+		task.onInactive()
+	}
+}
 
-	oldState, ok := proto.Clone(task.pb).(*pb.Task)
+// cloneTask returns a deep copy of a task's protobuf.
+func cloneTask(p *pb.Task) *pb.Task {
+	ret, ok := proto.Clone(p).(*pb.Task)
 	if !ok {
 		log.Fatalf("Failed to clone proto")
 	}
 
-	if updater != nil {
-		task.pb = updater(oldState)
+	return ret
+}
+
+// mergeChanges returns a copy of `current` with the fields that differ between `base` and `updated` taken from
+// `updated`, unless they differ between `base` and `current` as well.
+func mergeChanges(base, updated, current *pb.Task) *pb.Task {
+	ret := cloneTask(current)
+	b, u, c, r := base.ProtoReflect(), updated.ProtoReflect(), current.ProtoReflect(), ret.ProtoReflect()
+	fields := b.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fieldEqual(b, u, fd) || !fieldEqual(b, c, fd) {
+			continue
+		}
+		if u.Has(fd) {
+			r.Set(fd, u.Get(fd))
+		} else {
+			r.Clear(fd)
+		}
 	}
 
-	// Rebuild the children protobufs.
-	// This is terribly inefficient, but probably the easiest thing to do.
-	task.pb.Children = make([]*pb.Task, len(task.children))
-	for i, c := range task.children {
-		task.pb.Children[i] = c.Proto(nil)
+	return ret
+}
+
+// fieldEqual reports whether the field `fd` has the same value in `a` and `b`.
+func fieldEqual(a, b protoreflect.Message, fd protoreflect.FieldDescriptor) bool {
+	x, y := a.New(), b.New()
+	if a.Has(fd) {
+		x.Set(fd, a.Get(fd))
+	}
+	if b.Has(fd) {
+		y.Set(fd, b.Get(fd))
 	}
 
-	return task.pb
+	return proto.Equal(x.Interface(), y.Interface())
 }
 
 // SetState is a shortcut for atomically setting a state in the proto
 func (task *Task) SetState(state pb.TaskState) {
-	task.Proto(func(pb *pb.Task) *pb.Task {
+	setState(task, state, pb.TransitionSource_SOURCE_TASK)
+}
+
+// setState atomically sets a state of any task.
+func setState(task TaskInterface, state pb.TaskState, source pb.TransitionSource) {
+	updateProto(task, source, func(pb *pb.Task) *pb.Task {
 		pb.State = state
 		return pb
 	})
 }
 
+// Transitions returns the task's state machine, used to validate state changes requested via the API.
+func (task *Task) Transitions() Transitions {
+	return task.transitions
+}
+
+// SetTransitions overrides the task's state machine; it should be called before the task is used.
+func (task *Task) SetTransitions(t Transitions) {
+	task.transitions = t
+}
+
+// Name returns the task's name; it never changes after the task is created.
+func (task *Task) Name() string {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+
+	return task.pb.GetName()
+}
+
+// Cancel cancels the task's context and all the children. Task types holding background work not bound to the
+// task's context should override it.
+func (task *Task) Cancel() {
+	task.mu.Lock()
+	task.cancelContext()
+	task.mu.Unlock()
+
+	if task.onCancel != nil {
+		task.onCancel()
+	}
+
+	for _, c := range task.Children() {
+		c.Cancel()
+	}
+}
+
 // GetChild returns a child with the specified name
-func (task *Task) GetChild(name string) *Task {
-	for _, c := range task.children {
-		if c.pb.Name == name {
+func (task *Task) GetChild(name string) TaskInterface {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+
+	if i, ok := task.index[name]; ok {
+		return task.children[i]
+	}
+
+	return nil
+}
+
+// childGetter finds a child by its name without listing all the children.
+type childGetter interface {
+	GetChild(string) TaskInterface
+}
+
+// getChild returns a child of any task with the specified name
+func getChild(task TaskInterface, name string) TaskInterface {
+	if t, ok := task.(childGetter); ok {
+		return t.GetChild(name)
+	}
+
+	for _, c := range task.Children() {
+		if c.Name() == name {
 			return c
 		}
 	}
@@ -104,19 +387,65 @@ func (task *Task) GetChild(name string) *Task {
 	return nil
 }
 
-func (nt *Task) Add(task *Task) error {
+// Children returns a snapshot of the task's children.
+func (task *Task) Children() []TaskInterface {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+
+	ret := make([]TaskInterface, len(task.children))
+	copy(ret, task.children)
+
+	return ret
+}
+
+func (nt *Task) Add(task TaskInterface) error {
 	if !nt.has_children {
 		return ErrNoChildrenAllowed
 	}
 
-	newName := task.Proto(nil).Name
+	newName := task.Name()
 
-	for _, child := range nt.children {
-		if child.Proto(nil).Name == newName {
-			return fmt.Errorf("task named '%s' already exists", child.Proto(nil).Name)
-		}
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+
+	if _, ok := nt.index[newName]; ok {
+		return fmt.Errorf("task named '%s' already exists", newName)
 	}
+	nt.index[newName] = len(nt.children)
 	nt.children = append(nt.children, task)
+	if s, ok := task.(snapshotter); ok {
+		s.setParent(nt)
+	}
+	nt.markDirty()
+
+	return nil
+}
+
+// Remove removes the child with the specified name and cancels it.
+func (nt *Task) Remove(name string) error {
+	nt.mu.Lock()
+	var removed TaskInterface
+	if i, ok := nt.index[name]; ok {
+		removed = nt.children[i]
+		nt.children = append(nt.children[:i:i], nt.children[i+1:]...)
+		delete(nt.index, name)
+		// Keep the insertion order; the following children move one position back.
+		for name, j := range nt.index {
+			if j > i {
+				nt.index[name] = j - 1
+			}
+		}
+	}
+	nt.mu.Unlock()
+
+	if removed == nil {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, name)
+	}
+	if s, ok := removed.(snapshotter); ok {
+		s.setParent(nil)
+	}
+	nt.markDirty()
+	removed.Cancel()
 
 	return nil
 }
@@ -3,6 +3,7 @@ package rnr
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
 
 	"github.com/mplzik/rnr/golang/pkg/pb"
@@ -42,10 +43,10 @@ func TestTaskSchedState(t *testing.T) {
 	}
 }
 
-func newMockTask(name string, finalState pb.TaskState, pollCount *int) *Task {
+func newMockTask(name string, finalState pb.TaskState, pollCount *int32) *Task {
 	return NewTask(name, false, func(ctx context.Context, task *Task) {
 		if pollCount != nil {
-			*pollCount++
+			atomic.AddInt32(pollCount, 1)
 		}
 
 		task.Proto(func(state *pb.Task) *pb.Task {
@@ -58,7 +59,7 @@ func newMockTask(name string, finalState pb.TaskState, pollCount *int) *Task {
 	})
 }
 
-func compareTaskStates(t *testing.T, tasks []*Task, states []pb.TaskState) {
+func compareTaskStates(t *testing.T, tasks []TaskInterface, states []pb.TaskState) {
 	if len(tasks) != len(states) {
 		t.Errorf("`tasks` and `states` should have the same length (%d != %d)", len(tasks), len(states))
 	}
@@ -71,3 +72,87 @@ func compareTaskStates(t *testing.T, tasks []*Task, states []pb.TaskState) {
 		}
 	}
 }
+
+// counterTask is an example of a custom task type carrying its own state.
+type counterTask struct {
+	*Task
+	polls     int
+	cancelled bool
+}
+
+func newCounterTask(name string) *counterTask {
+	ret := &counterTask{}
+	ret.Task = NewTask(name, false, ret.poll)
+
+	return ret
+}
+
+func (ct *counterTask) poll(ctx context.Context, task *Task) {
+	ct.polls++
+	if ct.polls == 2 {
+		task.SetState(pb.TaskState_SUCCESS)
+	}
+}
+
+func (ct *counterTask) Cancel() {
+	ct.cancelled = true
+	ct.Task.Cancel()
+}
+
+func TestTask_CustomType(t *testing.T) {
+	ctx := context.TODO()
+	nt := NewNestedTask("nested task test", NestedTaskOptions{})
+	ct := newCounterTask("counter")
+
+	if err := nt.Add(ct); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nt.SetState(pb.TaskState_RUNNING)
+
+	tasks := []TaskInterface{ct, nt}
+
+	nt.Poll(ctx)
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_RUNNING, pb.TaskState_RUNNING})
+
+	nt.Poll(ctx)
+	compareTaskStates(t, tasks, []pb.TaskState{pb.TaskState_SUCCESS, pb.TaskState_SUCCESS})
+
+	if ct.polls != 2 {
+		t.Errorf("expecting custom task to be polled 2 times, got %d", ct.polls)
+	}
+
+	nt.Cancel()
+	if !ct.cancelled {
+		t.Errorf("expecting Cancel to be propagated to the children")
+	}
+}
+
+func TestTask_ProtoConcurrentUpdate(t *testing.T) {
+	task := NewTask("task", false, nil)
+
+	calls := 0
+	p := task.Proto(func(p *pb.Task) *pb.Task {
+		calls++
+		// The updater runs without the task's lock, so it can change the task itself; its changes are then merged.
+		task.SetPriority(2)
+		p.Message = "updated"
+		return p
+	})
+
+	if calls != 1 {
+		t.Errorf("expecting the updater to be called once, got %d", calls)
+	}
+	if p.Priority != 2 || p.Message != "updated" {
+		t.Errorf("unexpected protobuf %v", p)
+	}
+
+	// An update based on a state the task has left is dropped.
+	p = task.Proto(func(p *pb.Task) *pb.Task {
+		task.SetState(pb.TaskState_RUNNING)
+		p.Message = p.State.String()
+		return p
+	})
+	if p.State != pb.TaskState_RUNNING || p.Message != "updated" {
+		t.Errorf("unexpected protobuf %v", p)
+	}
+}
@@ -0,0 +1,99 @@
+package rnr
+
+import (
+	"fmt"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+// Transitions is a declarative state machine, mapping each state to the states it's allowed to transition to.
+// Transitions to the same state are always allowed.
+type Transitions map[pb.TaskState][]pb.TaskState
+
+var (
+	allStates = []pb.TaskState{
+		pb.TaskState_PENDING,
+		pb.TaskState_RUNNING,
+		pb.TaskState_SUCCESS,
+		pb.TaskState_FAILED,
+		pb.TaskState_SKIPPED,
+		pb.TaskState_ACTION_NEEDED,
+	}
+
+	// DefaultTransitions is used by tasks that don't provide their own state machine.
+	// Finished tasks can't be resumed directly; they have to be reset to PENDING (or their final state changed).
+	DefaultTransitions = Transitions{
+		pb.TaskState_UNKNOWN:       allStates,
+		pb.TaskState_PENDING:       allStates,
+		pb.TaskState_RUNNING:       allStates,
+		pb.TaskState_ACTION_NEEDED: allStates,
+		pb.TaskState_SUCCESS:       {pb.TaskState_PENDING, pb.TaskState_FAILED, pb.TaskState_SKIPPED},
+		pb.TaskState_FAILED:        {pb.TaskState_PENDING, pb.TaskState_SUCCESS, pb.TaskState_SKIPPED},
+		pb.TaskState_SKIPPED:       {pb.TaskState_PENDING, pb.TaskState_SUCCESS, pb.TaskState_FAILED},
+	}
+)
+
+// Allowed returns whether the transition `from` -> `to` is allowed.
+func (t Transitions) Allowed(from, to pb.TaskState) bool {
+	if from == to {
+		return true
+	}
+
+	for _, s := range t[from] {
+		if s == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TransitionValidator can be implemented by task types to override DefaultTransitions.
+type TransitionValidator interface {
+	Transitions() Transitions
+}
+
+// IllegalTransitionError is returned when a state transition is rejected by the task's state machine.
+type IllegalTransitionError struct {
+	Task string
+	From pb.TaskState
+	To   pb.TaskState
+}
+
+func (e *IllegalTransitionError) Error() string {
+	return fmt.Sprintf("task '%s' can't transition from %s to %s", e.Task, e.From, e.To)
+}
+
+// taskTransitions returns the state machine of any task.
+func taskTransitions(task TaskInterface) Transitions {
+	if tv, ok := task.(TransitionValidator); ok {
+		if t := tv.Transitions(); t != nil {
+			return t
+		}
+	}
+
+	return DefaultTransitions
+}
+
+// changeState atomically validates and sets a state of any task; `force` skips the validation.
+func changeState(task TaskInterface, state pb.TaskState, source pb.TransitionSource, force bool) error {
+	transitions := taskTransitions(task)
+
+	var err error
+	updateProto(task, source, func(p *pb.Task) *pb.Task {
+		err = transition(p, transitions, state, force)
+		return p
+	})
+
+	return err
+}
+
+// transition sets the state of the task's protobuf `p` if `transitions` allow it; `force` skips the validation.
+func transition(p *pb.Task, transitions Transitions, state pb.TaskState, force bool) error {
+	if !force && !transitions.Allowed(p.State, state) {
+		return &IllegalTransitionError{Task: p.Name, From: p.State, To: state}
+	}
+
+	p.State = state
+	return nil
+}
@@ -0,0 +1,95 @@
+package rnr
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mplzik/rnr/golang/pkg/pb"
+)
+
+func TestTransitions_Allowed(t *testing.T) {
+	tests := []struct {
+		from, to pb.TaskState
+		allowed  bool
+	}{
+		{pb.TaskState_PENDING, pb.TaskState_RUNNING, true},
+		{pb.TaskState_RUNNING, pb.TaskState_SUCCESS, true},
+		{pb.TaskState_SUCCESS, pb.TaskState_SUCCESS, true},
+		{pb.TaskState_SUCCESS, pb.TaskState_PENDING, true},
+		{pb.TaskState_SUCCESS, pb.TaskState_RUNNING, false},
+		{pb.TaskState_FAILED, pb.TaskState_ACTION_NEEDED, false},
+		{pb.TaskState_RUNNING, pb.TaskState_UNKNOWN, false},
+	}
+
+	for _, tt := range tests {
+		if got := DefaultTransitions.Allowed(tt.from, tt.to); got != tt.allowed {
+			t.Errorf("expecting %v -> %v to be allowed=%t, got %t", tt.from, tt.to, tt.allowed, got)
+		}
+	}
+}
+
+func TestJob_TaskRequestTransitions(t *testing.T) {
+	nt := NewNestedTask("root", NestedTaskOptions{})
+	ct := newMockTask("child", pb.TaskState_SUCCESS, nil)
+	st := NewShellTask("shell", "true")
+	nt.Add(ct)
+	nt.Add(st)
+	j := NewJob(nt)
+
+	ct.SetState(pb.TaskState_SUCCESS)
+	st.SetState(pb.TaskState_SUCCESS)
+
+	err := j.TaskRequest(&pb.TaskRequest{Path: []string{"child"}, State: pb.TaskState_RUNNING})
+	var ite *IllegalTransitionError
+	if !errors.As(err, &ite) {
+		t.Fatalf("expecting IllegalTransitionError, got %v", err)
+	}
+	if ite.From != pb.TaskState_SUCCESS || ite.To != pb.TaskState_RUNNING {
+		t.Errorf("unexpected transition in error: %v", ite)
+	}
+	compareTaskStates(t, []TaskInterface{ct}, []pb.TaskState{pb.TaskState_SUCCESS})
+
+	if err := j.TaskRequest(&pb.TaskRequest{Path: []string{"child"}, State: pb.TaskState_RUNNING, Force: true}); err != nil {
+		t.Fatalf("unexpected error when forcing a transition: %v", err)
+	}
+	compareTaskStates(t, []TaskInterface{ct}, []pb.TaskState{pb.TaskState_RUNNING})
+
+	// Shell tasks can't be rerun
+	if err := j.TaskRequest(&pb.TaskRequest{Path: []string{"shell"}, State: pb.TaskState_PENDING}); !errors.As(err, &ite) {
+		t.Fatalf("expecting IllegalTransitionError for a shell task, got %v", err)
+	}
+
+	if err := j.TaskRequest(&pb.TaskRequest{Path: []string{"foo"}, State: pb.TaskState_PENDING}); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expecting ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestRnrWebServer_TaskRequestStatus(t *testing.T) {
+	nt := NewNestedTask("root", NestedTaskOptions{})
+	ct := newMockTask("child", pb.TaskState_SUCCESS, nil)
+	nt.Add(ct)
+	ct.SetState(pb.TaskState_SUCCESS)
+	ws := NewRnrWebserver(NewJob(nt))
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"path": ["child"], "state": "SKIPPED"}`, http.StatusOK},
+		{`{"path": ["child"], "state": "RUNNING"}`, http.StatusConflict},
+		{`{"path": ["child"], "state": "RUNNING", "force": true}`, http.StatusOK},
+		{`{"path": ["foo"], "state": "RUNNING"}`, http.StatusNotFound},
+		{`{"path": `, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		ws.tasksHandler(rec, httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(tt.body)))
+		if rec.Code != tt.code {
+			t.Errorf("expecting status %d for %s, got %d", tt.code, tt.body, rec.Code)
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package rnr
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/mplzik/rnr/golang/pkg/pb"
@@ -12,6 +14,11 @@ import (
 
 type RnrWebServer struct {
 	job *Job
+
+	// Identify returns the identity of the operator making the request, e.g. based on an authentication header.
+	// If it's not set or returns an empty string, approvals are recorded under the user name used for basic
+	// authentication, the approver named in the request or the client's address, whichever is available first.
+	Identify func(*http.Request) string
 }
 
 func NewRnrWebserver(job *Job) *RnrWebServer {
@@ -40,19 +47,319 @@ func (rnr *RnrWebServer) tasksHandler(w http.ResponseWriter, r *http.Request) {
 		err := jsonpb.Unmarshal(r.Body, tr)
 		if err != nil {
 			log.Printf("Failed to convert body to JSON: %s", err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 		fmt.Println(tr)
 		err = rnr.job.TaskRequest(tr)
 		if err != nil {
 			log.Printf("Failed to process task request %s: %s", tr, err.Error())
+			http.Error(w, err.Error(), taskRequestStatus(err))
+			return
 		}
 		w.Write([]byte{})
 	}
 }
 
+// queryHandler returns the tasks matching the `pattern` query parameter (all tasks by default). The results can be
+// further filtered using the `state` (can be repeated) and `leaf` query parameters.
+func (rnr *RnrWebServer) queryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	pattern := q.Get("pattern")
+	if pattern == "" {
+		pattern = "**"
+	}
+
+	var predicates []TaskPredicate
+	if states := q["state"]; len(states) > 0 {
+		var s []pb.TaskState
+		for _, name := range states {
+			v, ok := pb.TaskState_value[name]
+			if !ok {
+				http.Error(w, fmt.Sprintf("unknown state '%s'", name), http.StatusBadRequest)
+				return
+			}
+			s = append(s, pb.TaskState(v))
+		}
+		predicates = append(predicates, InState(s...))
+	}
+	if leaf, err := strconv.ParseBool(q.Get("leaf")); err == nil && leaf {
+		predicates = append(predicates, IsLeaf)
+	}
+
+	matches, err := rnr.job.Query(pattern, predicates...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ret := &pb.QueryResult{}
+	for _, m := range matches {
+		tpb := m.Task.Proto(nil)
+		tpb.Children = nil
+		ret.Matches = append(ret.Matches, &pb.TaskMatch{Path: m.Path, Task: tpb})
+	}
+
+	m := jsonpb.Marshaler{
+		EmitDefaults: true,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := m.Marshal(w, ret); err != nil {
+		log.Printf("Failed to convert query result to json: %s", err.Error())
+	}
+}
+
+// poolsHandler returns the job's resource pools along with the tasks holding and waiting for them.
+func (rnr *RnrWebServer) poolsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	m := jsonpb.Marshaler{
+		EmitDefaults: true,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := m.Marshal(w, rnr.job.Pools()); err != nil {
+		log.Printf("Failed to convert pools to json: %s", err.Error())
+	}
+}
+
+// logsHandler returns the log of the task at `path` (the query parameter can be repeated, one path segment each).
+// Only the lines since `since` are returned, limited to the last `tail` lines if set. With `follow` set, the lines are
+// streamed as newline-delimited JSON until the client disconnects.
+func (rnr *RnrWebServer) logsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	var since, tail uint64
+	var follow bool
+	var err error
+	if v := q.Get("since"); v != "" {
+		if since, err = strconv.ParseUint(v, 10, 64); err != nil {
+			http.Error(w, fmt.Sprintf("invalid since: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := q.Get("tail"); v != "" {
+		if tail, err = strconv.ParseUint(v, 10, 64); err != nil {
+			http.Error(w, fmt.Sprintf("invalid tail: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := q.Get("follow"); v != "" {
+		if follow, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, fmt.Sprintf("invalid follow: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+	}
+
+	task := rnr.job.Find(q["path"])
+	if task == nil {
+		http.Error(w, fmt.Sprintf("%s: %v", ErrTaskNotFound.Error(), q["path"]), http.StatusNotFound)
+		return
+	}
+	src, ok := task.(LogSource)
+	if !ok {
+		http.Error(w, "task doesn't keep a log", http.StatusNotFound)
+		return
+	}
+
+	// Subscribe before fetching the lines, so that no update is missed.
+	var updated <-chan struct{}
+	if follow {
+		updated = src.LogsUpdated()
+	}
+	logs := src.Logs(since)
+	if tail > 0 && uint64(len(logs.Lines)) > tail {
+		logs.Lines = logs.Lines[uint64(len(logs.Lines))-tail:]
+	}
+
+	m := jsonpb.Marshaler{
+		EmitDefaults: true,
+	}
+
+	if !follow {
+		w.Header().Set("Content-Type", "application/json")
+		if err := m.Marshal(w, logs); err != nil {
+			log.Printf("Failed to convert task logs to json: %s", err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	for {
+		for _, line := range logs.Lines {
+			if err := m.Marshal(w, line); err != nil {
+				log.Printf("Failed to convert a log line to json: %s", err.Error())
+				return
+			}
+			w.Write([]byte("\n"))
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		select {
+		case <-updated:
+		case <-r.Context().Done():
+			return
+		}
+
+		updated = src.LogsUpdated()
+		logs = src.Logs(logs.Next)
+	}
+}
+
+// approvalHandler records an operator's decision on an approval gate.
+func (rnr *RnrWebServer) approvalHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ar := &pb.ApprovalRequest{}
+	if err := jsonpb.Unmarshal(r.Body, ar); err != nil {
+		log.Printf("Failed to convert body to JSON: %s", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ar.Approver = rnr.approver(r, ar.Approver)
+
+	if err := rnr.job.ApprovalRequest(ar); err != nil {
+		log.Printf("Failed to process approval request %s: %s", ar, err.Error())
+		http.Error(w, err.Error(), taskRequestStatus(err))
+		return
+	}
+	log.Printf("Approval request processed: %s", ar)
+	w.Write([]byte{})
+}
+
+// waitHandler skips or extends the wait of a wait task.
+func (rnr *RnrWebServer) waitHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	wr := &pb.WaitRequest{}
+	if err := jsonpb.Unmarshal(r.Body, wr); err != nil {
+		log.Printf("Failed to convert body to JSON: %s", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := rnr.job.WaitRequest(wr); err != nil {
+		log.Printf("Failed to process wait request %s: %s", wr, err.Error())
+		http.Error(w, err.Error(), taskRequestStatus(err))
+		return
+	}
+	w.Write([]byte{})
+}
+
+// pauseHandler pauses or resumes a subtree of tasks.
+func (rnr *RnrWebServer) pauseHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pr := &pb.PauseRequest{}
+	if err := jsonpb.Unmarshal(r.Body, pr); err != nil {
+		log.Printf("Failed to convert body to JSON: %s", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := rnr.job.PauseRequest(pr); err != nil {
+		log.Printf("Failed to process pause request %s: %s", pr, err.Error())
+		http.Error(w, err.Error(), taskRequestStatus(err))
+		return
+	}
+	log.Printf("Pause request processed: %s", pr)
+	w.Write([]byte{})
+}
+
+// priorityHandler changes the priority of a task.
+func (rnr *RnrWebServer) priorityHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pr := &pb.PriorityRequest{}
+	if err := jsonpb.Unmarshal(r.Body, pr); err != nil {
+		log.Printf("Failed to convert body to JSON: %s", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := rnr.job.PriorityRequest(pr); err != nil {
+		log.Printf("Failed to process priority request %s: %s", pr, err.Error())
+		http.Error(w, err.Error(), taskRequestStatus(err))
+		return
+	}
+	log.Printf("Priority request processed: %s", pr)
+	w.Write([]byte{})
+}
+
+// approver returns the identity of the operator making the request; an authenticated identity wins over the
+// claimed one.
+func (rnr *RnrWebServer) approver(r *http.Request, claimed string) string {
+	if rnr.Identify != nil {
+		if id := rnr.Identify(r); id != "" {
+			return id
+		}
+	}
+	if user, _, ok := r.BasicAuth(); ok && user != "" {
+		return user
+	}
+	if claimed != "" {
+		// Nobody is authenticated, so there's nothing better than the client's word.
+		return claimed
+	}
+
+	return r.RemoteAddr
+}
+
+// taskRequestStatus maps an error returned by Job.TaskRequest to a HTTP status code.
+func taskRequestStatus(err error) int {
+	var ite *IllegalTransitionError
+
+	switch {
+	case errors.As(err, &ite):
+		return http.StatusConflict
+	case errors.Is(err, ErrTaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrParamsNotEditable), errors.Is(err, ErrNotAwaitingApproval), errors.Is(err, ErrNotWaiting),
+		errors.Is(err, ErrNotPausable), errors.Is(err, ErrNotPaused):
+		return http.StatusConflict
+	case errors.Is(err, ErrParamNotFound), errors.Is(err, ErrInvalidParam), errors.Is(err, ErrNotAGate),
+		errors.Is(err, ErrNoDecision), errors.Is(err, ErrNotAWaitTask), errors.Is(err, ErrInvalidWait):
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
+
 func (rnr *RnrWebServer) RegisterHttp(urlPrefix string) {
 	fs := http.FileServer(http.FS(ui.Content))
 	http.Handle(urlPrefix+"/", fs)
 	http.HandleFunc(urlPrefix+"/tasks", rnr.tasksHandler)
+	http.HandleFunc(urlPrefix+"/query", rnr.queryHandler)
+	http.HandleFunc(urlPrefix+"/logs", rnr.logsHandler)
+	http.HandleFunc(urlPrefix+"/approval", rnr.approvalHandler)
+	http.HandleFunc(urlPrefix+"/wait", rnr.waitHandler)
+	http.HandleFunc(urlPrefix+"/priority", rnr.priorityHandler)
+	http.HandleFunc(urlPrefix+"/pause", rnr.pauseHandler)
+	http.HandleFunc(urlPrefix+"/pools", rnr.poolsHandler)
 }
@@ -13,12 +13,21 @@ from google.protobuf import symbol_database as _symbol_database
 _sym_db = _symbol_database.Default()
 
 
+from google.protobuf import duration_pb2 as google_dot_protobuf_dot_duration__pb2
+from google.protobuf import timestamp_pb2 as google_dot_protobuf_dot_timestamp__pb2
+from google.protobuf import struct_pb2 as google_dot_protobuf_dot_struct__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0btasks.proto\x12\x03rnr\"=\n\x03Job\x12\x0f\n\x07version\x18\x01 \x01(\x03\x12\x0c\n\x04uuid\x18\x02 \x01(\t\x12\x17\n\x04root\x18\x03 \x01(\x0b\x32\t.rnr.Task\"a\n\x04Task\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x1d\n\x05state\x18\x03 \x01(\x0e\x32\x0e.rnr.TaskState\x12\x0f\n\x07message\x18\x04 \x01(\t\x12\x1b\n\x08\x63hildren\x18\x05 \x03(\x0b\x32\t.rnr.Task\":\n\x0bTaskRequest\x12\x0c\n\x04path\x18\x01 \x03(\t\x12\x1d\n\x05state\x18\x02 \x01(\x0e\x32\x0e.rnr.TaskState*k\n\tTaskState\x12\x0b\n\x07UNKNOWN\x10\x00\x12\x0b\n\x07PENDING\x10\x01\x12\x0b\n\x07RUNNING\x10\x02\x12\x0b\n\x07SUCCESS\x10\x03\x12\n\n\x06\x46\x41ILED\x10\x04\x12\x0b\n\x07SKIPPED\x10\x05\x12\x11\n\rACTION_NEEDED\x10\x06\x42\x06Z\x04./pbb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0btasks.proto\x12\x03rnr\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xbe\x01\n\x0fStateTransition\x12\"\n\nfrom_state\x18\x01 \x01(\x0e\x32\x0e.rnr.TaskState\x12 \n\x08to_state\x18\x02 \x01(\x0e\x32\x0e.rnr.TaskState\x12-\n\ttimestamp\x18\x03 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x0f\n\x07message\x18\x04 \x01(\t\x12%\n\x06source\x18\x05 \x01(\x0e\x32\x15.rnr.TransitionSource\"d\n\x0bRetryStatus\x12\x0f\n\x07\x61ttempt\x18\x01 \x01(\x05\x12\x14\n\x0cmax_attempts\x18\x02 \x01(\x05\x12.\n\nnext_retry\x18\x03 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\"=\n\x03Job\x12\x0f\n\x07version\x18\x01 \x01(\x03\x12\x0c\n\x04uuid\x18\x02 \x01(\t\x12\x17\n\x04root\x18\x03 \x01(\x0b\x32\t.rnr.Task\"\xf4\x05\n\x04Task\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x1d\n\x05state\x18\x03 \x01(\x0e\x32\x0e.rnr.TaskState\x12\x0f\n\x07message\x18\x04 \x01(\t\x12\x1b\n\x08\x63hildren\x18\x05 \x03(\x0b\x32\t.rnr.Task\x12+\n\x07\x63reated\x18\x06 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12+\n\x07started\x18\x07 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12,\n\x08\x66inished\x18\x08 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12/\n\x0blast_polled\x18\t \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12+\n\x08\x64uration\x18\n \x01(\x0b\x32\x19.google.protobuf.Duration\x12%\n\x07history\x18\x0b \x03(\x0b\x32\x14.rnr.StateTransition\x12\x1f\n\x05retry\x18\x0c \x01(\x0b\x32\x10.rnr.RetryStatus\x12\x13\n\x0bstack_trace\x18\r \x01(\t\x12\'\n\x07outputs\x18\x0e \x03(\x0b\x32\x16.rnr.Task.OutputsEntry\x12\x1a\n\x06params\x18\x0f \x03(\x0b\x32\n.rnr.Param\x12\x12\n\ndepends_on\x18\x10 \x03(\t\x12\x1f\n\x08\x61pproval\x18\x11 \x01(\x0b\x32\r.rnr.Approval\x12.\n\nwait_until\x18\x12 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x10\n\x08priority\x18\x13 \x01(\x05\x12%\n\tresources\x18\x14 \x03(\x0b\x32\x12.rnr.ResourceClaim\x12#\n\x0bpaused_from\x18\x15 \x01(\x0e\x32\x0e.rnr.TaskState\x1a\x46\n\x0cOutputsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12%\n\x05value\x18\x02 \x01(\x0b\x32\x16.google.protobuf.Value:\x02\x38\x01\",\n\rResourceClaim\x12\x0c\n\x04pool\x18\x01 \x01(\t\x12\r\n\x05units\x18\x02 \x01(\x05\"R\n\x08PoolUser\x12\x0c\n\x04path\x18\x01 \x03(\t\x12\r\n\x05units\x18\x02 \x01(\x05\x12)\n\x05since\x18\x03 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\"t\n\x04Pool\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x10\n\x08\x63\x61pacity\x18\x02 \x01(\x05\x12\x0c\n\x04used\x18\x03 \x01(\x05\x12\x1e\n\x07holders\x18\x04 \x03(\x0b\x32\r.rnr.PoolUser\x12\x1e\n\x07waiters\x18\x05 \x03(\x0b\x32\r.rnr.PoolUser\"!\n\x05Pools\x12\x18\n\x05pools\x18\x01 \x03(\x0b\x32\t.rnr.Pool\"\x85\x01\n\x08\x41pproval\x12\'\n\x08\x64\x65\x63ision\x18\x01 \x01(\x0e\x32\x15.rnr.ApprovalDecision\x12\x10\n\x08\x61pprover\x18\x02 \x01(\t\x12-\n\ttimestamp\x18\x03 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x0f\n\x07\x63omment\x18\x04 \x01(\t\"k\n\x0f\x41pprovalRequest\x12\x0c\n\x04path\x18\x01 \x03(\t\x12\'\n\x08\x64\x65\x63ision\x18\x02 \x01(\x0e\x32\x15.rnr.ApprovalDecision\x12\x10\n\x08\x61pprover\x18\x03 \x01(\t\x12\x0f\n\x07\x63omment\x18\x04 \x01(\t\"T\n\x0bWaitRequest\x12\x0c\n\x04path\x18\x01 \x03(\t\x12\x0c\n\x04skip\x18\x02 \x01(\x08\x12)\n\x06\x65xtend\x18\x03 \x01(\x0b\x32\x19.google.protobuf.Duration\"=\n\x0cPauseRequest\x12\x0c\n\x04path\x18\x01 \x03(\t\x12\x0e\n\x06resume\x18\x02 \x01(\x08\x12\x0f\n\x07suspend\x18\x03 \x01(\x08\"1\n\x0fPriorityRequest\x12\x0c\n\x04path\x18\x01 \x03(\t\x12\x10\n\x08priority\x18\x02 \x01(\x05\"\x9e\x01\n\x05Param\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x1c\n\x04type\x18\x02 \x01(\x0e\x32\x0e.rnr.ParamType\x12\x13\n\x0b\x64\x65scription\x18\x03 \x01(\t\x12-\n\rdefault_value\x18\x04 \x01(\x0b\x32\x16.google.protobuf.Value\x12%\n\x05value\x18\x05 \x01(\x0b\x32\x16.google.protobuf.Value\"\xcf\x01\n\x0bTaskRequest\x12\x0c\n\x04path\x18\x01 \x03(\t\x12\x1d\n\x05state\x18\x02 \x01(\x0e\x32\x0e.rnr.TaskState\x12\r\n\x05\x66orce\x18\x03 \x01(\x08\x12\x0f\n\x07pattern\x18\x04 \x01(\t\x12,\n\x06params\x18\x05 \x03(\x0b\x32\x1c.rnr.TaskRequest.ParamsEntry\x1a\x45\n\x0bParamsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12%\n\x05value\x18\x02 \x01(\x0b\x32\x16.google.protobuf.Value:\x02\x38\x01\"2\n\tTaskMatch\x12\x0c\n\x04path\x18\x01 \x03(\t\x12\x17\n\x04task\x18\x02 \x01(\x0b\x32\t.rnr.Task\".\n\x0bQueryResult\x12\x1f\n\x07matches\x18\x01 \x03(\x0b\x32\x0e.rnr.TaskMatch\"S\n\x07LogLine\x12\x0b\n\x03seq\x18\x01 \x01(\x04\x12-\n\ttimestamp\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x0c\n\x04text\x18\x03 \x01(\t\"5\n\x08TaskLogs\x12\x1b\n\x05lines\x18\x01 \x03(\x0b\x32\x0c.rnr.LogLine\x12\x0c\n\x04next\x18\x02 \x01(\x04*w\n\tTaskState\x12\x0b\n\x07UNKNOWN\x10\x00\x12\x0b\n\x07PENDING\x10\x01\x12\x0b\n\x07RUNNING\x10\x02\x12\x0b\n\x07SUCCESS\x10\x03\x12\n\n\x06\x46\x41ILED\x10\x04\x12\x0b\n\x07SKIPPED\x10\x05\x12\x11\n\rACTION_NEEDED\x10\x06\x12\n\n\x06PAUSED\x10\x07*a\n\x10TransitionSource\x12\x12\n\x0eSOURCE_UNKNOWN\x10\x00\x12\x14\n\x10SOURCE_SCHEDULER\x10\x01\x12\x0f\n\x0bSOURCE_TASK\x10\x02\x12\x12\n\x0eSOURCE_REQUEST\x10\x03*V\n\x10\x41pprovalDecision\x12\x14\n\x10\x44\x45\x43ISION_PENDING\x10\x00\x12\x15\n\x11\x44\x45\x43ISION_APPROVED\x10\x01\x12\x15\n\x11\x44\x45\x43ISION_REJECTED\x10\x02*M\n\tParamType\x12\x10\n\x0cPARAM_STRING\x10\x00\x12\r\n\tPARAM_INT\x10\x01\x12\x0f\n\x0bPARAM_FLOAT\x10\x02\x12\x0e\n\nPARAM_BOOL\x10\x03\x42\x06Z\x04./pbb\x06proto3')
 
 _TASKSTATE = DESCRIPTOR.enum_types_by_name['TaskState']
 TaskState = enum_type_wrapper.EnumTypeWrapper(_TASKSTATE)
+_TRANSITIONSOURCE = DESCRIPTOR.enum_types_by_name['TransitionSource']
+TransitionSource = enum_type_wrapper.EnumTypeWrapper(_TRANSITIONSOURCE)
+_APPROVALDECISION = DESCRIPTOR.enum_types_by_name['ApprovalDecision']
+ApprovalDecision = enum_type_wrapper.EnumTypeWrapper(_APPROVALDECISION)
+_PARAMTYPE = DESCRIPTOR.enum_types_by_name['ParamType']
+ParamType = enum_type_wrapper.EnumTypeWrapper(_PARAMTYPE)
 UNKNOWN = 0
 PENDING = 1
 RUNNING = 2
@@ -26,11 +35,55 @@ SUCCESS = 3
 FAILED = 4
 SKIPPED = 5
 ACTION_NEEDED = 6
+PAUSED = 7
+SOURCE_UNKNOWN = 0
+SOURCE_SCHEDULER = 1
+SOURCE_TASK = 2
+SOURCE_REQUEST = 3
+DECISION_PENDING = 0
+DECISION_APPROVED = 1
+DECISION_REJECTED = 2
+PARAM_STRING = 0
+PARAM_INT = 1
+PARAM_FLOAT = 2
+PARAM_BOOL = 3
 
 
+_STATETRANSITION = DESCRIPTOR.message_types_by_name['StateTransition']
+_RETRYSTATUS = DESCRIPTOR.message_types_by_name['RetryStatus']
 _JOB = DESCRIPTOR.message_types_by_name['Job']
 _TASK = DESCRIPTOR.message_types_by_name['Task']
+_TASK_OUTPUTSENTRY = _TASK.nested_types_by_name['OutputsEntry']
+_RESOURCECLAIM = DESCRIPTOR.message_types_by_name['ResourceClaim']
+_POOLUSER = DESCRIPTOR.message_types_by_name['PoolUser']
+_POOL = DESCRIPTOR.message_types_by_name['Pool']
+_POOLS = DESCRIPTOR.message_types_by_name['Pools']
+_APPROVAL = DESCRIPTOR.message_types_by_name['Approval']
+_APPROVALREQUEST = DESCRIPTOR.message_types_by_name['ApprovalRequest']
+_WAITREQUEST = DESCRIPTOR.message_types_by_name['WaitRequest']
+_PAUSEREQUEST = DESCRIPTOR.message_types_by_name['PauseRequest']
+_PRIORITYREQUEST = DESCRIPTOR.message_types_by_name['PriorityRequest']
+_PARAM = DESCRIPTOR.message_types_by_name['Param']
 _TASKREQUEST = DESCRIPTOR.message_types_by_name['TaskRequest']
+_TASKREQUEST_PARAMSENTRY = _TASKREQUEST.nested_types_by_name['ParamsEntry']
+_TASKMATCH = DESCRIPTOR.message_types_by_name['TaskMatch']
+_QUERYRESULT = DESCRIPTOR.message_types_by_name['QueryResult']
+_LOGLINE = DESCRIPTOR.message_types_by_name['LogLine']
+_TASKLOGS = DESCRIPTOR.message_types_by_name['TaskLogs']
+StateTransition = _reflection.GeneratedProtocolMessageType('StateTransition', (_message.Message,), {
+  'DESCRIPTOR' : _STATETRANSITION,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.StateTransition)
+  })
+_sym_db.RegisterMessage(StateTransition)
+
+RetryStatus = _reflection.GeneratedProtocolMessageType('RetryStatus', (_message.Message,), {
+  'DESCRIPTOR' : _RETRYSTATUS,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.RetryStatus)
+  })
+_sym_db.RegisterMessage(RetryStatus)
+
 Job = _reflection.GeneratedProtocolMessageType('Job', (_message.Message,), {
   'DESCRIPTOR' : _JOB,
   '__module__' : 'tasks_pb2'
@@ -39,29 +92,183 @@ Job = _reflection.GeneratedProtocolMessageType('Job', (_message.Message,), {
 _sym_db.RegisterMessage(Job)
 
 Task = _reflection.GeneratedProtocolMessageType('Task', (_message.Message,), {
+
+  'OutputsEntry' : _reflection.GeneratedProtocolMessageType('OutputsEntry', (_message.Message,), {
+    'DESCRIPTOR' : _TASK_OUTPUTSENTRY,
+    '__module__' : 'tasks_pb2'
+    # @@protoc_insertion_point(class_scope:rnr.Task.OutputsEntry)
+    })
   'DESCRIPTOR' : _TASK,
   '__module__' : 'tasks_pb2'
   # @@protoc_insertion_point(class_scope:rnr.Task)
   })
 _sym_db.RegisterMessage(Task)
+_sym_db.RegisterMessage(Task.OutputsEntry)
+
+ResourceClaim = _reflection.GeneratedProtocolMessageType('ResourceClaim', (_message.Message,), {
+  'DESCRIPTOR' : _RESOURCECLAIM,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.ResourceClaim)
+  })
+_sym_db.RegisterMessage(ResourceClaim)
+
+PoolUser = _reflection.GeneratedProtocolMessageType('PoolUser', (_message.Message,), {
+  'DESCRIPTOR' : _POOLUSER,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.PoolUser)
+  })
+_sym_db.RegisterMessage(PoolUser)
+
+Pool = _reflection.GeneratedProtocolMessageType('Pool', (_message.Message,), {
+  'DESCRIPTOR' : _POOL,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.Pool)
+  })
+_sym_db.RegisterMessage(Pool)
+
+Pools = _reflection.GeneratedProtocolMessageType('Pools', (_message.Message,), {
+  'DESCRIPTOR' : _POOLS,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.Pools)
+  })
+_sym_db.RegisterMessage(Pools)
+
+Approval = _reflection.GeneratedProtocolMessageType('Approval', (_message.Message,), {
+  'DESCRIPTOR' : _APPROVAL,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.Approval)
+  })
+_sym_db.RegisterMessage(Approval)
+
+ApprovalRequest = _reflection.GeneratedProtocolMessageType('ApprovalRequest', (_message.Message,), {
+  'DESCRIPTOR' : _APPROVALREQUEST,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.ApprovalRequest)
+  })
+_sym_db.RegisterMessage(ApprovalRequest)
+
+WaitRequest = _reflection.GeneratedProtocolMessageType('WaitRequest', (_message.Message,), {
+  'DESCRIPTOR' : _WAITREQUEST,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.WaitRequest)
+  })
+_sym_db.RegisterMessage(WaitRequest)
+
+PauseRequest = _reflection.GeneratedProtocolMessageType('PauseRequest', (_message.Message,), {
+  'DESCRIPTOR' : _PAUSEREQUEST,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.PauseRequest)
+  })
+_sym_db.RegisterMessage(PauseRequest)
+
+PriorityRequest = _reflection.GeneratedProtocolMessageType('PriorityRequest', (_message.Message,), {
+  'DESCRIPTOR' : _PRIORITYREQUEST,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.PriorityRequest)
+  })
+_sym_db.RegisterMessage(PriorityRequest)
+
+Param = _reflection.GeneratedProtocolMessageType('Param', (_message.Message,), {
+  'DESCRIPTOR' : _PARAM,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.Param)
+  })
+_sym_db.RegisterMessage(Param)
 
 TaskRequest = _reflection.GeneratedProtocolMessageType('TaskRequest', (_message.Message,), {
+
+  'ParamsEntry' : _reflection.GeneratedProtocolMessageType('ParamsEntry', (_message.Message,), {
+    'DESCRIPTOR' : _TASKREQUEST_PARAMSENTRY,
+    '__module__' : 'tasks_pb2'
+    # @@protoc_insertion_point(class_scope:rnr.TaskRequest.ParamsEntry)
+    })
   'DESCRIPTOR' : _TASKREQUEST,
   '__module__' : 'tasks_pb2'
   # @@protoc_insertion_point(class_scope:rnr.TaskRequest)
   })
 _sym_db.RegisterMessage(TaskRequest)
+_sym_db.RegisterMessage(TaskRequest.ParamsEntry)
+
+TaskMatch = _reflection.GeneratedProtocolMessageType('TaskMatch', (_message.Message,), {
+  'DESCRIPTOR' : _TASKMATCH,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.TaskMatch)
+  })
+_sym_db.RegisterMessage(TaskMatch)
+
+QueryResult = _reflection.GeneratedProtocolMessageType('QueryResult', (_message.Message,), {
+  'DESCRIPTOR' : _QUERYRESULT,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.QueryResult)
+  })
+_sym_db.RegisterMessage(QueryResult)
+
+LogLine = _reflection.GeneratedProtocolMessageType('LogLine', (_message.Message,), {
+  'DESCRIPTOR' : _LOGLINE,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.LogLine)
+  })
+_sym_db.RegisterMessage(LogLine)
+
+TaskLogs = _reflection.GeneratedProtocolMessageType('TaskLogs', (_message.Message,), {
+  'DESCRIPTOR' : _TASKLOGS,
+  '__module__' : 'tasks_pb2'
+  # @@protoc_insertion_point(class_scope:rnr.TaskLogs)
+  })
+_sym_db.RegisterMessage(TaskLogs)
 
 if _descriptor._USE_C_DESCRIPTORS == False:
 
   DESCRIPTOR._options = None
   DESCRIPTOR._serialized_options = b'Z\004./pb'
-  _TASKSTATE._serialized_start=242
-  _TASKSTATE._serialized_end=349
-  _JOB._serialized_start=20
-  _JOB._serialized_end=81
-  _TASK._serialized_start=83
-  _TASK._serialized_end=180
-  _TASKREQUEST._serialized_start=182
-  _TASKREQUEST._serialized_end=240
+  _TASKSTATE._serialized_start=2571
+  _TASKSTATE._serialized_end=2690
+  _TRANSITIONSOURCE._serialized_start=2692
+  _TRANSITIONSOURCE._serialized_end=2789
+  _APPROVALDECISION._serialized_start=2791
+  _APPROVALDECISION._serialized_end=2877
+  _PARAMTYPE._serialized_start=2879
+  _PARAMTYPE._serialized_end=2956
+  _STATETRANSITION._serialized_start=116
+  _STATETRANSITION._serialized_end=306
+  _RETRYSTATUS._serialized_start=308
+  _RETRYSTATUS._serialized_end=408
+  _JOB._serialized_start=410
+  _JOB._serialized_end=471
+  _TASK._serialized_start=474
+  _TASK._serialized_end=1230
+  _TASK_OUTPUTSENTRY._serialized_start=1160
+  _TASK_OUTPUTSENTRY._serialized_end=1230
+  _RESOURCECLAIM._serialized_start=1232
+  _RESOURCECLAIM._serialized_end=1276
+  _POOLUSER._serialized_start=1278
+  _POOLUSER._serialized_end=1360
+  _POOL._serialized_start=1362
+  _POOL._serialized_end=1478
+  _POOLS._serialized_start=1480
+  _POOLS._serialized_end=1513
+  _APPROVAL._serialized_start=1516
+  _APPROVAL._serialized_end=1649
+  _APPROVALREQUEST._serialized_start=1651
+  _APPROVALREQUEST._serialized_end=1758
+  _WAITREQUEST._serialized_start=1760
+  _WAITREQUEST._serialized_end=1844
+  _PAUSEREQUEST._serialized_start=1846
+  _PAUSEREQUEST._serialized_end=1907
+  _PRIORITYREQUEST._serialized_start=1909
+  _PRIORITYREQUEST._serialized_end=1958
+  _PARAM._serialized_start=1961
+  _PARAM._serialized_end=2119
+  _TASKREQUEST._serialized_start=2122
+  _TASKREQUEST._serialized_end=2329
+  _TASKREQUEST_PARAMSENTRY._serialized_start=2260
+  _TASKREQUEST_PARAMSENTRY._serialized_end=2329
+  _TASKMATCH._serialized_start=2331
+  _TASKMATCH._serialized_end=2381
+  _QUERYRESULT._serialized_start=2383
+  _QUERYRESULT._serialized_end=2429
+  _LOGLINE._serialized_start=2431
+  _LOGLINE._serialized_end=2514
+  _TASKLOGS._serialized_start=2516
+  _TASKLOGS._serialized_end=2569
 # @@protoc_insertion_point(module_scope)
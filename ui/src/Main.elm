@@ -1,9 +1,12 @@
 module Main exposing (..)
 
 import Browser
+import Dict exposing (Dict)
 import Html exposing (..)
 import Html.Attributes exposing (..)
 import Http
+import Json.Decode
+import Json.Encode
 import Time
 import Proto exposing (..)
 import Html.Events exposing (onClick)
@@ -44,6 +47,11 @@ type Msg
   = GotJob (Result Http.Error Job)
   | Tick Time.Posix
   | PostTaskRequest (List String) String
+  | PostTaskParam (List String) String String
+  | PostApproval (List String) String
+  | PostWait (List String) Bool (Maybe String)
+  | PostPriority (List String) Int
+  | PostPause (List String) Bool
   | TaskRequestPosted (Result Http.Error ())
 
 update : Msg -> Model -> (Model, Cmd Msg)
@@ -59,7 +67,27 @@ update msg model =
     Tick _ -> (model, updateTasks)
     PostTaskRequest path state -> (model, Http.post
       { url = "/tasks"
-      , body = Http.jsonBody (Proto.taskRequestEncoder { path = path, state = (taskStateFromString state |> Maybe.withDefault Proto.Unknown)} )
+      , body = Http.jsonBody (Proto.taskRequestEncoder { path = path, state = Just (taskStateFromString state |> Maybe.withDefault Proto.Unknown), params = []} )
+      , expect = Http.expectWhatever TaskRequestPosted })
+    PostTaskParam path name value -> (model, Http.post
+      { url = "/tasks"
+      , body = Http.jsonBody (Proto.taskRequestEncoder { path = path, state = Nothing, params = [ (name, value) ]} )
+      , expect = Http.expectWhatever TaskRequestPosted })
+    PostApproval path decision -> (model, Http.post
+      { url = "/approval"
+      , body = Http.jsonBody (Proto.approvalRequestEncoder { path = path, decision = decision } )
+      , expect = Http.expectWhatever TaskRequestPosted })
+    PostWait path skip extend -> (model, Http.post
+      { url = "/wait"
+      , body = Http.jsonBody (Proto.waitRequestEncoder { path = path, skip = skip, extend = extend } )
+      , expect = Http.expectWhatever TaskRequestPosted })
+    PostPriority path priority -> (model, Http.post
+      { url = "/priority"
+      , body = Http.jsonBody (Proto.priorityRequestEncoder { path = path, priority = priority } )
+      , expect = Http.expectWhatever TaskRequestPosted })
+    PostPause path resume -> (model, Http.post
+      { url = "/pause"
+      , body = Http.jsonBody (Proto.pauseRequestEncoder { path = path, resume = resume } )
       , expect = Http.expectWhatever TaskRequestPosted })
     TaskRequestPosted _ -> (model, Cmd.none)
 
@@ -112,6 +140,7 @@ taskStyle task = case task.state of
   "SUCCESS" -> [ attribute "style" "color: green" ]
   "FAILED" -> [ attribute "style" "color: darkred" ]
   "ACTION_NEEDED" -> [ attribute "style" "color: orange" ]
+  "PAUSED" -> [ attribute "style" "color: steelblue" ]
   _ -> []
 
 hrefRegex : Regex.Regex
@@ -132,11 +161,150 @@ autolink message =
       in
         (if leftPrefix /= "" then [text leftPrefix] else []) ++ [a [attribute "href" href.match] [text href.match]] ++ autolink (String.dropLeft (href.index + String.length href.match) message)
 
+-- Formats a protobuf JSON duration (i.e. "3723.5s") in a human-readable way (i.e. "1h 2m 3s").
+formatDuration : String -> String
+formatDuration d =
+  case String.toFloat (String.dropRight 1 d) of
+    Nothing -> d
+    Just seconds ->
+      let
+        total = floor seconds
+        h = total // 3600
+        m = (modBy 3600 total) // 60
+        s = modBy 60 total
+      in
+        if h > 0 then String.fromInt h ++ "h " ++ String.fromInt m ++ "m " ++ String.fromInt s ++ "s"
+        else if m > 0 then String.fromInt m ++ "m " ++ String.fromInt s ++ "s"
+        else String.fromInt s ++ "s"
+
+timestampsTitle : Task -> String
+timestampsTitle task =
+  [ ("created", task.created), ("started", task.started), ("finished", task.finished), ("last polled", task.lastPolled) ]
+    |> List.filterMap (\(label, ts) -> Maybe.map (\t -> label ++ ": " ++ t) ts)
+    |> String.join "\n"
+
+viewTaskDuration : Task -> List (Html Msg)
+viewTaskDuration task = case task.duration of
+  Nothing -> []
+  Just d -> [ text " ", span [ attribute "style" "color: grey" ] [ text ("(" ++ formatDuration d ++ ")") ] ]
+
+viewTaskRetry : Task -> List (Html Msg)
+viewTaskRetry task = case task.retry of
+  Nothing -> []
+  Just r ->
+    let
+      next = Maybe.map (\ts -> ", next retry at " ++ ts) r.nextRetry |> Maybe.withDefault ""
+    in
+      [ text " ", span [ attribute "style" "color: grey" ] [ text ("[attempt " ++ String.fromInt r.attempt ++ "/" ++ String.fromInt r.maxAttempts ++ next ++ "]") ] ]
+
+formatOutputs : Dict String Json.Encode.Value -> String
+formatOutputs outputs =
+  Dict.toList outputs
+    |> List.map (\(key, v) -> key ++ "=" ++ Json.Encode.encode 0 v)
+    |> String.join ", "
+
+viewTaskOutputs : Task -> List (Html Msg)
+viewTaskOutputs task =
+  if Dict.isEmpty task.outputs then []
+  else [ text " ", span [ attribute "style" "color: grey" ] [ text ("{" ++ formatOutputs task.outputs ++ "}") ] ]
+
+formatParamValue : Maybe Json.Encode.Value -> String
+formatParamValue v = case v of
+  Nothing -> ""
+  Just json -> case Json.Decode.decodeValue Json.Decode.string json of
+    Ok s -> s
+    Err _ -> Json.Encode.encode 0 json
+
+paramsEditable : Task -> Bool
+paramsEditable task = task.state == "PENDING" || task.state == "ACTION_NEEDED"
+
+viewTaskParams : List String -> Task -> List (Html Msg)
+viewTaskParams path task =
+  List.map (\p -> span [ title p.description ]
+    [ text (" " ++ p.name ++ ": ")
+    , input
+      [ value (formatParamValue p.value)
+      , disabled (not (paramsEditable task))
+      , size 8
+      , Html.Events.on "change" (Json.Decode.map (PostTaskParam path p.name) Html.Events.targetValue)
+      ] []
+    ]) task.params
+
+viewTaskDependencies : Task -> List (Html Msg)
+viewTaskDependencies task =
+  if List.isEmpty task.dependsOn then []
+  else [ text " ", span [ attribute "style" "color: grey" ] [ text ("after " ++ String.join ", " task.dependsOn) ] ]
+
+formatApproval : Approval -> String
+formatApproval a =
+  let
+    verb = if a.decision == "DECISION_APPROVED" then "approved" else "rejected"
+    at = Maybe.map (\ts -> " at " ++ ts) a.timestamp |> Maybe.withDefault ""
+    comment = if String.isEmpty a.comment then "" else ": " ++ a.comment
+  in
+    verb ++ " by " ++ a.approver ++ at ++ comment
+
+viewTaskApproval : List String -> Task -> List (Html Msg)
+viewTaskApproval path task = case task.approval of
+  Nothing -> []
+  Just a ->
+    if a.decision /= "DECISION_PENDING" then
+      [ text " ", span [ attribute "style" "color: grey" ] [ text ("[" ++ formatApproval a ++ "]") ] ]
+    else if task.state == "ACTION_NEEDED" then
+      [ text " "
+      , button [ onClick (PostApproval path "DECISION_APPROVED") ] [ text "Approve" ]
+      , button [ onClick (PostApproval path "DECISION_REJECTED") ] [ text "Reject" ]
+      ]
+    else []
+
+viewTaskWait : List String -> Task -> List (Html Msg)
+viewTaskWait path task = case task.waitUntil of
+  Nothing -> []
+  Just until ->
+    [ text " ", span [ attribute "style" "color: grey" ] [ text ("[until " ++ until ++ "]") ] ]
+    ++ (if task.state == "RUNNING" then
+      [ text " "
+      , button [ onClick (PostWait path True Nothing) ] [ text "Skip wait" ]
+      , button [ onClick (PostWait path False (Just "600s")) ] [ text "+10m" ]
+      ]
+    else [])
+
+viewTaskPriority : List String -> Task -> List (Html Msg)
+viewTaskPriority path task =
+  (if task.priority == 0 then [] else [ text " ", span [ attribute "style" "color: grey" ] [ text ("priority " ++ String.fromInt task.priority) ] ])
+  ++ (if task.state == "PENDING" then
+    [ text " ", button [ onClick (PostPriority path (task.priority + 1)), title "Start this task sooner" ] [ text "▲" ] ]
+  else [])
+
+hasChildren : Task -> Bool
+hasChildren task =
+  let
+    (Children children) = task.children
+  in
+    not (List.isEmpty children)
+
+viewTaskPause : List String -> Task -> List (Html Msg)
+viewTaskPause path task =
+  if task.state == "PAUSED" then
+    [ text " ", button [ onClick (PostPause path True) ] [ text "Resume" ] ]
+  else if hasChildren task && (task.state == "RUNNING" || task.state == "ACTION_NEEDED") then
+    [ text " ", button [ onClick (PostPause path False) ] [ text "Pause" ] ]
+  else []
+
 viewTaskHeadline : List String -> Task -> Html Msg
-viewTaskHeadline path task = span [] [ 
-  span (taskStyle task) [ viewTaskState path task, text " ", text task.name ]
-  , text " ", i [] (autolink task.message)
-  ]
+viewTaskHeadline path task = span [ title (timestampsTitle task) ] ([ 
+  span (taskStyle task) [ viewTaskState path task, text " ", text task.name ] ]
+  ++ viewTaskDependencies task
+  ++ viewTaskDuration task
+  ++ viewTaskRetry task
+  ++ viewTaskOutputs task
+  ++ viewTaskParams path task
+  ++ viewTaskApproval path task
+  ++ viewTaskWait path task
+  ++ viewTaskPriority path task
+  ++ viewTaskPause path task
+  ++ [ text " ", i [] (autolink task.message) ]
+  )
 
 viewTaskState : List String -> Task -> Html Msg
 viewTaskState path task = select [ Html.Events.onInput (PostTaskRequest path) ] (
@@ -1,17 +1,38 @@
 module Proto exposing (..)
 
+import Dict exposing (Dict)
 import Json.Decode exposing (..)
 import Json.Encode as Encode
 import Json.Decode.Extra exposing (..)
 
-type alias Task = { name : String, state : String, message : String, children: Children }
+type alias Task =
+  { name : String
+  , state : String
+  , message : String
+  , children: Children
+  , created : Maybe String
+  , started : Maybe String
+  , finished : Maybe String
+  , lastPolled : Maybe String
+  , duration : Maybe String
+  , retry : Maybe RetryStatus
+  , outputs : Dict String Value
+  , params : List Param
+  , dependsOn : List String
+  , approval : Maybe Approval
+  , waitUntil : Maybe String
+  , priority : Int
+  }
 type Children = Children (List Task)
+type alias RetryStatus = { attempt : Int, maxAttempts : Int, nextRetry : Maybe String }
+type alias Approval = { decision : String, approver : String, timestamp : Maybe String, comment : String }
+type alias Param = { name : String, type_ : String, description : String, value : Maybe Value }
 type alias Job = { version: Int, uuid : String, root : Task }
 
-type TaskState = Unknown | Pending | Running | Success | Failed | Skipped | ActionNeeded
+type TaskState = Unknown | Pending | Running | Success | Failed | Skipped | ActionNeeded | Paused
 
 taskStateStrings : List (TaskState, String)
-taskStateStrings = [ (Unknown, "UNKNOWN"), (Pending, "PENDING"), (Running, "RUNNING"), (Success, "SUCCESS"), (Failed, "FAILED"), (Skipped, "SKIPPED"), (ActionNeeded, "ACTION_NEEDED") ]
+taskStateStrings = [ (Unknown, "UNKNOWN"), (Pending, "PENDING"), (Running, "RUNNING"), (Success, "SUCCESS"), (Failed, "FAILED"), (Skipped, "SKIPPED"), (ActionNeeded, "ACTION_NEEDED"), (Paused, "PAUSED") ]
 
 taskStateToString : TaskState -> String
 taskStateToString ts = List.filter (\(xts, _) -> xts == ts) taskStateStrings |> List.map Tuple.second |> List.head |> Maybe.withDefault ""
@@ -32,15 +53,80 @@ childrenDecoder =
 
 taskDecoder : Decoder Task
 taskDecoder =
-    map4 Task
+    succeed Task
+      |> andMap (field "name" string)
+      |> andMap (field "state" string)
+      |> andMap (field "message" string)
+      |> andMap (field "children" childrenDecoder)
+      |> andMap (maybe (field "created" string))
+      |> andMap (maybe (field "started" string))
+      |> andMap (maybe (field "finished" string))
+      |> andMap (maybe (field "lastPolled" string))
+      |> andMap (maybe (field "duration" string))
+      |> andMap (maybe (field "retry" retryStatusDecoder))
+      |> andMap (oneOf [ field "outputs" (dict value), succeed Dict.empty ])
+      |> andMap (oneOf [ field "params" (list paramDecoder), succeed [] ])
+      |> andMap (oneOf [ field "dependsOn" (list string), succeed [] ])
+      |> andMap (maybe (field "approval" approvalDecoder))
+      |> andMap (maybe (field "waitUntil" string))
+      |> andMap (oneOf [ field "priority" int, succeed 0 ])
+
+retryStatusDecoder : Decoder RetryStatus
+retryStatusDecoder =
+    map3 RetryStatus
+      (field "attempt" int)
+      (field "maxAttempts" int)
+      (maybe (field "nextRetry" string))
+
+approvalDecoder : Decoder Approval
+approvalDecoder =
+    map4 Approval
+      (field "decision" string)
+      (field "approver" string)
+      (maybe (field "timestamp" string))
+      (field "comment" string)
+
+paramDecoder : Decoder Param
+paramDecoder =
+    map4 Param
       (field "name" string)
-      (field "state" string)
-      (field "message" string)
-      (field "children" childrenDecoder)
+      (field "type" string)
+      (field "description" string)
+      (maybe (field "value" value))
 
-type alias TaskRequest = { path: List String, state: TaskState }
+type alias TaskRequest = { path: List String, state: Maybe TaskState, params: List (String, String) }
 
 taskRequestEncoder : TaskRequest -> Encode.Value
-taskRequestEncoder td = Encode.object
-    [ ("path", Encode.list Encode.string td.path)
-    , ("state", Encode.string <| taskStateToString td.state)]
\ No newline at end of file
+taskRequestEncoder td = Encode.object (
+    [ ("path", Encode.list Encode.string td.path) ]
+    ++ (td.state |> Maybe.map (\s -> [ ("state", Encode.string <| taskStateToString s) ]) |> Maybe.withDefault [])
+    ++ (if List.isEmpty td.params then [] else [ ("params", Encode.object (List.map (\(k, v) -> (k, Encode.string v)) td.params)) ]))
+
+type alias ApprovalRequest = { path: List String, decision: String }
+
+approvalRequestEncoder : ApprovalRequest -> Encode.Value
+approvalRequestEncoder ar = Encode.object
+    [ ("path", Encode.list Encode.string ar.path)
+    , ("decision", Encode.string ar.decision) ]
+
+type alias WaitRequest = { path: List String, skip: Bool, extend: Maybe String }
+
+waitRequestEncoder : WaitRequest -> Encode.Value
+waitRequestEncoder wr = Encode.object (
+    [ ("path", Encode.list Encode.string wr.path)
+    , ("skip", Encode.bool wr.skip) ]
+    ++ (wr.extend |> Maybe.map (\d -> [ ("extend", Encode.string d) ]) |> Maybe.withDefault []))
+
+type alias PauseRequest = { path: List String, resume: Bool }
+
+pauseRequestEncoder : PauseRequest -> Encode.Value
+pauseRequestEncoder pr = Encode.object
+    [ ("path", Encode.list Encode.string pr.path)
+    , ("resume", Encode.bool pr.resume) ]
+
+type alias PriorityRequest = { path: List String, priority: Int }
+
+priorityRequestEncoder : PriorityRequest -> Encode.Value
+priorityRequestEncoder pr = Encode.object
+    [ ("path", Encode.list Encode.string pr.path)
+    , ("priority", Encode.int pr.priority) ]
@@ -4,8 +4,10 @@ import Expect exposing (Expectation)
 import Fuzz exposing (Fuzzer, int, list, string)
 import Test exposing (..)
 
+import Dict
 import Html exposing (..)
 import Html.Attributes exposing (..)
+import Json.Encode
 
 import Main
 
@@ -27,6 +29,38 @@ suite =
 
                 in
                     List.map (\(name, message, html) -> (test name (\_ -> Expect.equal html (Main.autolink message)))) tests
-
+        , describe "formatDuration" <|
+                let
+                    tests = [
+                        ("formats seconds", "3.5s", "3s"),
+                        ("formats minutes", "62s", "1m 2s"),
+                        ("formats hours", "3723.5s", "1h 2m 3s"),
+                        ("keeps unparseable durations", "foo", "foo") ]
+                in
+                    List.map (\(name, duration, expected) -> (test name (\_ -> Expect.equal expected (Main.formatDuration duration)))) tests
+        , describe "formatOutputs" <|
+                let
+                    tests = [
+                        ("formats no outputs", [], ""),
+                        ("formats strings and numbers", [("build", Json.Encode.string "1234"), ("count", Json.Encode.int 3)], "build=\"1234\", count=3"),
+                        ("formats lists", [("hosts", Json.Encode.list Json.Encode.string ["a", "b"])], "hosts=[\"a\",\"b\"]") ]
+                in
+                    List.map (\(name, outputs, expected) -> (test name (\_ -> Expect.equal expected (Main.formatOutputs (Dict.fromList outputs))))) tests
+        , describe "formatParamValue" <|
+                let
+                    tests = [
+                        ("formats unset values", Nothing, ""),
+                        ("formats strings without quotes", Just (Json.Encode.string "1.2.3"), "1.2.3"),
+                        ("formats numbers", Just (Json.Encode.int 10), "10"),
+                        ("formats bools", Just (Json.Encode.bool True), "true") ]
+                in
+                    List.map (\(name, v, expected) -> (test name (\_ -> Expect.equal expected (Main.formatParamValue v)))) tests
+        , describe "formatApproval" <|
+                let
+                    tests = [
+                        ("formats approvals", { decision = "DECISION_APPROVED", approver = "alice", timestamp = Just "2021-01-01T00:00:00Z", comment = "" }, "approved by alice at 2021-01-01T00:00:00Z"),
+                        ("formats rejections with a comment", { decision = "DECISION_REJECTED", approver = "bob", timestamp = Nothing, comment = "not now" }, "rejected by bob: not now") ]
+                in
+                    List.map (\(name, a, expected) -> (test name (\_ -> Expect.equal expected (Main.formatApproval a)))) tests
                 
         ]